@@ -17,15 +17,30 @@ limitations under the License.
 package v1alpha1
 
 import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// NetworkReference selects a subnet of a Network managed by Mission Control.
+type NetworkReference struct {
+	// Name of the Network resource.
+	Name string `json:"name"`
+	// Subnet of the Network, its first subnet when empty.
+	Subnet string `json:"subnet,omitempty"`
+}
+
 type ProviderData struct {
 	Name        string `json:"name,omitempty"`
 	Zone        string `json:"location,omitempty"`
 	MachineType string `json:"machineType,omitempty"`
 	Image       string `json:"image,omitempty"`
 	Network     string `json:"network,omitempty"`
+	// NetworkRef places the machine in a Network of the same Mission
+	// instead of the provider network named by Network.
+	NetworkRef *NetworkReference `json:"networkRef,omitempty"`
+	// SSHPublicKey is installed for the admin user on providers that require
+	// one at creation time, such as Azure.
+	SSHPublicKey string `json:"sshPublicKey,omitempty"`
 }
 
 type VirtualMachineMissionRef struct {
@@ -36,14 +51,37 @@ type VirtualMachineMissionRef struct {
 type VirtualMachineSpec struct {
 	MissionRef  VirtualMachineMissionRef `json:"missionRef,omitempty"`
 	ForProvider ProviderData             `json:"forProvider,omitempty"`
+	// Provider selects the Mission package to create the machine with. It is
+	// only needed when the mission key alone does not identify the package.
+	Provider string `json:"provider,omitempty"`
+	// DeletionPolicy decides whether the cloud resources are deleted or
+	// orphaned along with the VirtualMachine. Defaults to the Mission policy.
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
+// VirtualMachineStatus reports the machine as observed by the provider,
+// independently of which provider it runs on.
 type VirtualMachineStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	Provider               string `json:"provider,omitempty"`
+	InstanceID             string `json:"instanceId,omitempty"`
+	InternalIP             string `json:"internalIp,omitempty"`
+	ExternalIP             string `json:"externalIp,omitempty"`
+	// State is the machine state as reported by the provider, e.g. RUNNING.
+	State string `json:"state,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+//+kubebuilder:printcolumn:name="PROVIDER",type="string",JSONPath=".status.provider"
+//+kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="INTERNAL-IP",type="string",JSONPath=".status.internalIp"
+//+kubebuilder:printcolumn:name="EXTERNAL-IP",type="string",JSONPath=".status.externalIp"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // VirtualMachine is the Schema for the virtualmachines API
 type VirtualMachine struct {
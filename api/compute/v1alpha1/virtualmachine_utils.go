@@ -0,0 +1,329 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	awscomputev1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	azurev1 "github.com/upbound/provider-azure/apis/azure/v1beta1"
+	azurecomputev1 "github.com/upbound/provider-azure/apis/compute/v1beta1"
+	azurenetworkv1 "github.com/upbound/provider-azure/apis/network/v1beta1"
+	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	validation "k8s.io/apimachinery/pkg/util/validation"
+
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// Admin user created on providers that need one declared up front.
+const AdminUsername = "missioncontrol"
+
+// Network every provider creates machines in when none is given.
+const DefaultNetwork = "default"
+
+// Matches the region prefix of an AWS zone, e.g. "us-east-1" in "us-east-1a"
+// or "us-west-2" in the local zone "us-west-2-lax-1a".
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]*)?-[a-z]+-[0-9]+`)
+
+// AWSRegion returns the region a zone belongs to. Values that already are a
+// region, or do not look like an AWS zone, are returned unchanged.
+func AWSRegion(zone string) string {
+	if region := awsRegionPattern.FindString(zone); region != "" {
+		return region
+	}
+	return zone
+}
+
+// Compute Engine instance names follow RFC 1035.
+var gcpInstanceNamePattern = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// Azure limits Linux virtual machine names to 64 characters.
+const azureVMNameMaxLength = 64
+
+func (vm *VirtualMachine) GCPVerify() error {
+	if err := vm.GenericVerify(); err != nil {
+		return err
+	}
+	if !gcpInstanceNamePattern.MatchString(vm.Spec.ForProvider.Name) {
+		return errors.New("GCP instance names must start with a letter and have at most 63 lowercase letters, digits or hyphens.")
+	}
+	return nil
+}
+
+func (vm *VirtualMachine) AWSVerify() error {
+	return vm.GenericVerify()
+}
+
+func (vm *VirtualMachine) AzureVerify() error {
+	if err := vm.GenericVerify(); err != nil {
+		return err
+	}
+	data := vm.Spec.ForProvider
+	if len(data.Name) > azureVMNameMaxLength {
+		return fmt.Errorf("Azure virtual machine names can have at most %d characters.", azureVMNameMaxLength)
+	}
+	if data.SSHPublicKey == "" {
+		return errors.New("Azure virtual machines need an SSH public key for the admin user.")
+	}
+	if _, err := azureImageURN(data.Image); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GenericVerify checks that the machine name can be used as the name of the
+// managed resources created for it.
+func (vm *VirtualMachine) GenericVerify() error {
+	if vm.Spec.ForProvider.Name == "" {
+		return errors.New("Virtual machine name not filled.")
+	}
+	if errs := validation.IsDNS1123Subdomain(vm.Spec.ForProvider.Name); len(errs) != 0 {
+		return errors.New(strings.Join(errs, " "))
+	}
+	if ref := vm.Spec.ForProvider.NetworkRef; ref != nil {
+		if ref.Name == "" {
+			return errors.New("Network reference name not filled.")
+		}
+		if vm.Spec.ForProvider.Network != "" {
+			return errors.New("Virtual machines can either name a network or reference one, not both.")
+		}
+	}
+	return nil
+}
+
+// azureImageURN splits an Azure image URN, "publisher:offer:sku:version".
+func azureImageURN(image string) ([]string, error) {
+	urn := strings.Split(image, ":")
+	if len(urn) != 4 || slices.Contains(urn, "") {
+		return nil, fmt.Errorf("Azure images must be given as publisher:offer:sku:version, not %q.", image)
+	}
+	return urn, nil
+}
+
+// subnetRef returns the managed subnet the machine is placed in when it
+// references a Network. The reference is expected to be resolved by the
+// controller to the name the Network gives its managed resources and to
+// one of its subnets.
+func (vm *VirtualMachine) subnetRef() *xpv1.Reference {
+	ref := vm.Spec.ForProvider.NetworkRef
+	return &xpv1.Reference{Name: networkv1alpha1.SubnetResourceName(ref.Name, ref.Subnet)}
+}
+
+func (vm *VirtualMachine) Convert2GCP(providerConfig string) *gcpcomputev1.Instance {
+	nic := gcpcomputev1.NetworkInterfaceParameters{
+		Network: &vm.Spec.ForProvider.Network,
+	}
+	if ref := vm.Spec.ForProvider.NetworkRef; ref != nil {
+		nic = gcpcomputev1.NetworkInterfaceParameters{
+			NetworkRef:    &xpv1.Reference{Name: ref.Name},
+			SubnetworkRef: vm.subnetRef(),
+		}
+	}
+	return &gcpcomputev1.Instance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: vm.Spec.ForProvider.Name,
+		},
+		Spec: gcpcomputev1.InstanceSpec{
+			ForProvider: gcpcomputev1.InstanceParameters{
+				Zone:        &vm.Spec.ForProvider.Zone,
+				MachineType: &vm.Spec.ForProvider.MachineType,
+				BootDisk: []gcpcomputev1.BootDiskParameters{{
+					InitializeParams: []gcpcomputev1.InitializeParamsParameters{{
+						Image: &vm.Spec.ForProvider.Image,
+					}},
+				}},
+				NetworkInterface: []gcpcomputev1.NetworkInterfaceParameters{nic},
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{
+					Name: providerConfig,
+				},
+			},
+		},
+	}
+}
+
+func (vm *VirtualMachine) Convert2AWS(providerConfig string) *awscomputev1.Instance {
+	data := vm.Spec.ForProvider
+	region := AWSRegion(data.Zone)
+	params := awscomputev1.InstanceParameters{
+		Region: &region,
+	}
+	if data.Zone != region {
+		params.AvailabilityZone = &data.Zone
+	}
+	if data.Image != "" {
+		params.AMI = &data.Image
+	}
+	if data.MachineType != "" {
+		params.InstanceType = &data.MachineType
+	}
+	// An empty or "default" network launches into the default VPC, anything
+	// else is expected to be a subnet id. Referenced Networks also protect
+	// the machine with their security group.
+	if data.NetworkRef != nil {
+		params.SubnetIDRef = vm.subnetRef()
+		params.VPCSecurityGroupIDRefs = []xpv1.Reference{{Name: data.NetworkRef.Name}}
+	} else if data.Network != "" && data.Network != DefaultNetwork {
+		params.SubnetID = &data.Network
+	}
+	return &awscomputev1.Instance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: awscomputev1.InstanceSpec{
+			ForProvider: params,
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{
+					Name: providerConfig,
+				},
+			},
+		},
+	}
+}
+
+// Convert2Azure returns the resource group, network interface and virtual
+// machine that together make up an Azure VM. The image is expected as an
+// URN, "publisher:offer:sku:version", and the network as a subnet id unless
+// a Network is referenced or it is the default network, which Azure does not
+// have.
+func (vm *VirtualMachine) Convert2Azure(providerConfig string) (*azurev1.ResourceGroup, *azurenetworkv1.NetworkInterface, *azurecomputev1.LinuxVirtualMachine) {
+	data := vm.Spec.ForProvider
+	providerConfigRef := &xpv1.Reference{
+		Name: providerConfig,
+	}
+	resourceGroup := &azurev1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: utils.AzureResourceGroupName("VirtualMachine", data.Name),
+		},
+		Spec: azurev1.ResourceGroupSpec{
+			ForProvider: azurev1.ResourceGroupParameters{
+				Location: &data.Zone,
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	nicName := data.Name + "-nic"
+	ipConfig := azurenetworkv1.IPConfigurationParameters{
+		Name:                       &nicName,
+		PrivateIPAddressAllocation: utils.StrPtr("Dynamic"),
+	}
+	if data.NetworkRef != nil {
+		ipConfig.SubnetIDRef = vm.subnetRef()
+	} else if data.Network != "" && data.Network != DefaultNetwork {
+		ipConfig.SubnetID = &data.Network
+	}
+	nic := &azurenetworkv1.NetworkInterface{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nicName,
+		},
+		Spec: azurenetworkv1.NetworkInterfaceSpec{
+			ForProvider: azurenetworkv1.NetworkInterfaceParameters{
+				Location:             &data.Zone,
+				ResourceGroupNameRef: &xpv1.Reference{Name: resourceGroup.GetName()},
+				IPConfiguration:      []azurenetworkv1.IPConfigurationParameters{ipConfig},
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	params := azurecomputev1.LinuxVirtualMachineParameters{
+		Location:                &data.Zone,
+		Size:                    &data.MachineType,
+		ResourceGroupNameRef:    &xpv1.Reference{Name: resourceGroup.GetName()},
+		NetworkInterfaceIdsRefs: []xpv1.Reference{{Name: nic.GetName()}},
+		AdminUsername:           utils.StrPtr(AdminUsername),
+		OsDisk: []azurecomputev1.OsDiskParameters{{
+			Caching:            utils.StrPtr("ReadWrite"),
+			StorageAccountType: utils.StrPtr("Standard_LRS"),
+		}},
+	}
+	if data.SSHPublicKey != "" {
+		params.AdminSSHKey = []azurecomputev1.AdminSSHKeyParameters{{
+			PublicKey: &data.SSHPublicKey,
+			Username:  utils.StrPtr(AdminUsername),
+		}}
+	}
+	if urn, err := azureImageURN(data.Image); err == nil {
+		params.SourceImageReference = []azurecomputev1.SourceImageReferenceParameters{{
+			Publisher: &urn[0],
+			Offer:     &urn[1],
+			Sku:       &urn[2],
+			Version:   &urn[3],
+		}}
+	}
+	linuxvm := &azurecomputev1.LinuxVirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: azurecomputev1.LinuxVirtualMachineSpec{
+			ForProvider: params,
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	return resourceGroup, nic, linuxvm
+}
+
+// ObserveGCP fills the status with the details of the observed Compute
+// Engine instance.
+func (vm *VirtualMachine) ObserveGCP(instance *gcpcomputev1.Instance) {
+	observed := instance.Status.AtProvider
+	vm.Status.InstanceID = utils.StrValue(observed.InstanceID)
+	vm.Status.State = utils.StrValue(observed.CurrentStatus)
+	vm.Status.InternalIP, vm.Status.ExternalIP = "", ""
+	if len(observed.NetworkInterface) != 0 {
+		nic := observed.NetworkInterface[0]
+		vm.Status.InternalIP = utils.StrValue(nic.NetworkIP)
+		if len(nic.AccessConfig) != 0 {
+			vm.Status.ExternalIP = utils.StrValue(nic.AccessConfig[0].NatIP)
+		}
+	}
+	utils.MirrorConditions(&vm.Status.ConditionedStatus, instance)
+}
+
+// ObserveAWS fills the status with the details of the observed EC2 instance.
+func (vm *VirtualMachine) ObserveAWS(instance *awscomputev1.Instance) {
+	observed := instance.Status.AtProvider
+	vm.Status.InstanceID = utils.StrValue(observed.ID)
+	vm.Status.State = utils.StrValue(observed.InstanceState)
+	vm.Status.InternalIP = utils.StrValue(observed.PrivateIP)
+	vm.Status.ExternalIP = utils.StrValue(observed.PublicIP)
+	utils.MirrorConditions(&vm.Status.ConditionedStatus, instance)
+}
+
+// ObserveAzure fills the status with the details of the observed virtual
+// machine. Azure does not report a power state through Crossplane.
+func (vm *VirtualMachine) ObserveAzure(linuxvm *azurecomputev1.LinuxVirtualMachine) {
+	observed := linuxvm.Status.AtProvider
+	vm.Status.InstanceID = utils.StrValue(observed.VirtualMachineID)
+	vm.Status.State = ""
+	vm.Status.InternalIP = utils.StrValue(observed.PrivateIPAddress)
+	vm.Status.ExternalIP = utils.StrValue(observed.PublicIPAddress)
+	utils.MirrorConditions(&vm.Status.ConditionedStatus, linuxvm)
+}
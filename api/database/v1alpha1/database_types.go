@@ -0,0 +1,111 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProviderData describes the database on every provider. Its name, region,
+// engine and username cannot change once the database is created.
+type ProviderData struct {
+	Name   string `json:"name,omitempty"`
+	Region string `json:"region,omitempty"`
+	// +kubebuilder:validation:Enum=postgres;mysql
+	Engine string `json:"engine,omitempty"`
+	// Version of the engine, a major version such as "15" for postgres or
+	// "8.0" for mysql.
+	Version string `json:"version,omitempty"`
+	// Size is the compute tier of the database, translated into a machine
+	// type of the provider.
+	// +kubebuilder:validation:Enum=small;medium;large
+	Size string `json:"size,omitempty"`
+	// StorageGB is the disk size of the database in gigabytes.
+	StorageGB int `json:"storageGB,omitempty"`
+	// HighAvailability keeps a standby of the database in another zone of
+	// the region.
+	HighAvailability bool `json:"highAvailability,omitempty"`
+	// Username of the administrator account of the database. Its password
+	// is generated by the operator.
+	Username string `json:"username,omitempty"`
+}
+
+type DatabaseMissionRef struct {
+	MissionName string `json:"missionName,omitempty"`
+	MissionKey  string `json:"keyName,omitempty"`
+}
+
+type DatabaseSpec struct {
+	MissionRef  DatabaseMissionRef `json:"missionRef,omitempty"`
+	ForProvider ProviderData       `json:"forProvider,omitempty"`
+	// ConnectionSecretRef names the Secret the operator writes the
+	// endpoint, port, username and password of the database to. The Secret
+	// is created and owned by the Database and cannot change once the
+	// database is created.
+	ConnectionSecretRef xpv1.SecretReference `json:"connectionSecretRef"`
+	// Provider selects the Mission package to create the database with. It
+	// is only needed when the mission key alone does not identify the
+	// package.
+	Provider string `json:"provider,omitempty"`
+	// DeletionPolicy decides whether the cloud resources are deleted or
+	// orphaned along with the Database. Defaults to the Mission policy.
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// DatabaseStatus tells where the database can be reached, independently of
+// which provider it runs on.
+type DatabaseStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	Provider               string `json:"provider,omitempty"`
+	// Endpoint is the host name or address clients connect to.
+	Endpoint string `json:"endpoint,omitempty"`
+	Port     int    `json:"port,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+//+kubebuilder:printcolumn:name="PROVIDER",type="string",JSONPath=".status.provider"
+//+kubebuilder:printcolumn:name="ENGINE",type="string",JSONPath=".spec.forProvider.engine"
+//+kubebuilder:printcolumn:name="ENDPOINT",type="string",JSONPath=".status.endpoint"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Database is the Schema for the databases API
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseSpec   `json:"spec,omitempty"`
+	Status DatabaseStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatabaseList contains a list of Database
+type DatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Database `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Database{}, &DatabaseList{})
+}
@@ -0,0 +1,423 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	meta "github.com/crossplane/crossplane-runtime/pkg/meta"
+	awsrdsv1 "github.com/upbound/provider-aws/apis/rds/v1beta1"
+	azurev1 "github.com/upbound/provider-azure/apis/azure/v1beta1"
+	azuredbv1 "github.com/upbound/provider-azure/apis/dbforpostgresql/v1beta1"
+	gcpsqlv1 "github.com/upbound/provider-gcp/apis/sql/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	validation "k8s.io/apimachinery/pkg/util/validation"
+
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// Engines a database can run.
+const (
+	EnginePostgres = "postgres"
+	EngineMySQL    = "mysql"
+)
+
+// Size tiers of a database.
+const (
+	SizeSmall  = "small"
+	SizeMedium = "medium"
+	SizeLarge  = "large"
+)
+
+// Defaults applied to databases that leave the respective field empty.
+const (
+	DefaultSize      = SizeSmall
+	DefaultStorageGB = 20
+	DefaultUsername  = "missioncontrol"
+)
+
+// DefaultVersions are the engine versions used when none is given.
+var DefaultVersions = map[string]string{
+	EnginePostgres: "15",
+	EngineMySQL:    "8.0",
+}
+
+// enginePorts are the ports the engines listen on.
+var enginePorts = map[string]int{
+	EnginePostgres: 5432,
+	EngineMySQL:    3306,
+}
+
+// Machine types each provider runs the size tiers on.
+var (
+	gcpTiers = map[string]string{
+		SizeSmall:  "db-custom-1-3840",
+		SizeMedium: "db-custom-2-7680",
+		SizeLarge:  "db-custom-4-15360",
+	}
+	awsInstanceClasses = map[string]string{
+		SizeSmall:  "db.t3.small",
+		SizeMedium: "db.m5.large",
+		SizeLarge:  "db.m5.xlarge",
+	}
+	azureSkus = map[string]string{
+		SizeSmall:  "B_Standard_B1ms",
+		SizeMedium: "GP_Standard_D2s_v3",
+		SizeLarge:  "GP_Standard_D4s_v3",
+	}
+)
+
+// azureStorageGB are the only disk sizes Azure flexible servers accept.
+var azureStorageGB = []int{32, 64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768}
+
+// reservedUsernames are taken by the engines or by one of the providers.
+var reservedUsernames = []string{"admin", "administrator", "azure_pg_admin", "azure_superuser", "guest", "mysql", "postgres", "public", "rdsadmin", "root"}
+
+// Naming rules of database instances and users, without the length limits
+// that are checked separately.
+var (
+	versionPattern         = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+	usernamePattern        = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	gcpInstanceNamePattern = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+	awsIdentifierPattern   = regexp.MustCompile(`^[a-zA-Z](-?[a-zA-Z0-9])*$`)
+	azureServerNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+)
+
+func (d *Database) GCPVerify() error {
+	if err := d.GenericVerify(); err != nil {
+		return err
+	}
+	data := d.Spec.ForProvider
+	if !gcpInstanceNamePattern.MatchString(data.Name) || len(data.Name) > 98 {
+		return errors.New("Cloud SQL instance names must start with a letter and have at most 98 lowercase letters, digits or hyphens.")
+	}
+	if data.Engine == EngineMySQL && !strings.Contains(data.Version, ".") {
+		return errors.New("Cloud SQL mysql versions need a minor version, such as \"8.0\".")
+	}
+	if data.Engine == EnginePostgres && strings.Contains(data.Version, ".") {
+		return errors.New("Cloud SQL postgres versions are major versions only, such as \"15\".")
+	}
+	if data.StorageGB < 10 {
+		return errors.New("Cloud SQL instances need at least 10 GB of storage.")
+	}
+	return nil
+}
+
+func (d *Database) AWSVerify() error {
+	if err := d.GenericVerify(); err != nil {
+		return err
+	}
+	data := d.Spec.ForProvider
+	if !awsIdentifierPattern.MatchString(data.Name) || len(data.Name) > 63 {
+		return errors.New("RDS instance names must start with a letter and have at most 63 letters, digits or single hyphens.")
+	}
+	if data.StorageGB < 20 || data.StorageGB > 65536 {
+		return errors.New("RDS instances need between 20 and 65536 GB of storage.")
+	}
+	return nil
+}
+
+func (d *Database) AzureVerify() error {
+	if err := d.GenericVerify(); err != nil {
+		return err
+	}
+	data := d.Spec.ForProvider
+	if data.Engine != EnginePostgres {
+		return fmt.Errorf("Azure databases only support the %s engine.", EnginePostgres)
+	}
+	if strings.Contains(data.Version, ".") {
+		return errors.New("Azure postgres versions are major versions only, such as \"15\".")
+	}
+	if !azureServerNamePattern.MatchString(data.Name) || len(data.Name) < 3 || len(data.Name) > 63 {
+		return errors.New("Azure server names must have between 3 and 63 lowercase letters, digits or hyphens and start and end with a letter or digit.")
+	}
+	if !slices.Contains(azureStorageGB, data.StorageGB) {
+		return fmt.Errorf("Azure databases only support storage sizes of %s GB.", strings.Trim(fmt.Sprint(azureStorageGB), "[]"))
+	}
+	if data.HighAvailability && data.Size == SizeSmall {
+		return errors.New("Azure does not offer high availability for small databases.")
+	}
+	return nil
+}
+
+// GenericVerify checks the fields of the database every provider needs and
+// the Secret its connection details are written to.
+func (d *Database) GenericVerify() error {
+	data := d.Spec.ForProvider
+	if data.Name == "" {
+		return errors.New("Database name not filled.")
+	}
+	if errs := validation.IsDNS1123Subdomain(data.Name); len(errs) != 0 {
+		return errors.New(strings.Join(errs, " "))
+	}
+	if data.Region == "" {
+		return errors.New("Database region not filled.")
+	}
+	if _, ok := enginePorts[data.Engine]; !ok {
+		return fmt.Errorf("Database engine %q is not supported, use %s or %s.", data.Engine, EnginePostgres, EngineMySQL)
+	}
+	if !versionPattern.MatchString(data.Version) {
+		return fmt.Errorf("Database version %q is not valid.", data.Version)
+	}
+	if _, ok := gcpTiers[data.Size]; !ok {
+		return fmt.Errorf("Database size %q is not valid, use %s, %s or %s.", data.Size, SizeSmall, SizeMedium, SizeLarge)
+	}
+	if data.StorageGB <= 0 {
+		return errors.New("Database storage not filled.")
+	}
+	if err := verifyUsername(data.Engine, data.Username); err != nil {
+		return err
+	}
+	ref := d.Spec.ConnectionSecretRef
+	if ref.Name == "" || ref.Namespace == "" {
+		return errors.New("Connection secret name and namespace not filled.")
+	}
+	if errs := validation.IsDNS1123Subdomain(ref.Name); len(errs) != 0 {
+		return errors.New(strings.Join(errs, " "))
+	}
+	if errs := validation.IsDNS1123Label(ref.Namespace); len(errs) != 0 {
+		return errors.New(strings.Join(errs, " "))
+	}
+	return nil
+}
+
+// verifyUsername checks the administrator name against the rules of every
+// provider, mysql allows at most 16 characters.
+func verifyUsername(engine, username string) error {
+	if username == "" {
+		return errors.New("Database username not filled.")
+	}
+	maxLength := 63
+	if engine == EngineMySQL {
+		maxLength = 16
+	}
+	if !usernamePattern.MatchString(username) || len(username) > maxLength {
+		return fmt.Errorf("Database usernames must start with a letter and have at most %d lowercase letters, digits or underscores.", maxLength)
+	}
+	if slices.Contains(reservedUsernames, username) || strings.HasPrefix(username, "pg_") {
+		return fmt.Errorf("Database username %s is reserved.", username)
+	}
+	return nil
+}
+
+// ConnectionSecret returns the Secret holding the connection details of the
+// database with the given administrator password. The endpoint and port are
+// only added once the provider reported them.
+func (d *Database) ConnectionSecret(password string) *corev1.Secret {
+	data := map[string][]byte{
+		xpv1.ResourceCredentialsSecretUserKey:     []byte(d.Spec.ForProvider.Username),
+		xpv1.ResourceCredentialsSecretPasswordKey: []byte(password),
+	}
+	if d.Status.Endpoint != "" {
+		data[xpv1.ResourceCredentialsSecretEndpointKey] = []byte(d.Status.Endpoint)
+	}
+	if d.Status.Port != 0 {
+		data[xpv1.ResourceCredentialsSecretPortKey] = []byte(strconv.Itoa(d.Status.Port))
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      d.Spec.ConnectionSecretRef.Name,
+			Namespace: d.Spec.ConnectionSecretRef.Namespace,
+		},
+		Data: data,
+	}
+}
+
+// passwordRef points the managed resources to the password kept in the
+// connection Secret.
+func (d *Database) passwordRef() *xpv1.SecretKeySelector {
+	return &xpv1.SecretKeySelector{
+		SecretReference: d.Spec.ConnectionSecretRef,
+		Key:             xpv1.ResourceCredentialsSecretPasswordKey,
+	}
+}
+
+// Convert2GCP returns the Cloud SQL instance of the database and its
+// administrator user. Highly available instances are regional and keep
+// backups, which mysql also needs binary logs for.
+func (d *Database) Convert2GCP(providerConfig string) (*gcpsqlv1.DatabaseInstance, *gcpsqlv1.User) {
+	data := d.Spec.ForProvider
+	providerConfigRef := &xpv1.Reference{
+		Name: providerConfig,
+	}
+	version := strings.ToUpper(data.Engine) + "_" + strings.ReplaceAll(data.Version, ".", "_")
+	availability := "ZONAL"
+	var backups []gcpsqlv1.BackupConfigurationParameters
+	if data.HighAvailability {
+		availability = "REGIONAL"
+		enabled := true
+		backup := gcpsqlv1.BackupConfigurationParameters{Enabled: &enabled}
+		if data.Engine == EngineMySQL {
+			backup.BinaryLogEnabled = &enabled
+		}
+		backups = append(backups, backup)
+	}
+	diskSize := float64(data.StorageGB)
+	// Deletion is governed by the deletion policy of the Database instead.
+	deletionProtection := false
+	instance := &gcpsqlv1.DatabaseInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: gcpsqlv1.DatabaseInstanceSpec{
+			ForProvider: gcpsqlv1.DatabaseInstanceParameters{
+				DatabaseVersion:    &version,
+				DeletionProtection: &deletionProtection,
+				Region:             utils.StrPtr(data.Region),
+				Settings: []gcpsqlv1.SettingsParameters{{
+					Tier:                utils.StrPtr(gcpTiers[data.Size]),
+					AvailabilityType:    &availability,
+					DiskSize:            &diskSize,
+					DiskType:            utils.StrPtr("PD_SSD"),
+					BackupConfiguration: backups,
+				}},
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	user := &gcpsqlv1.User{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: gcpsqlv1.UserSpec{
+			ForProvider: gcpsqlv1.UserParameters{
+				InstanceRef:       &xpv1.Reference{Name: instance.GetName()},
+				PasswordSecretRef: d.passwordRef(),
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	// The user is created under its own name, not the one of the resource.
+	meta.SetExternalName(user, data.Username)
+	return instance, user
+}
+
+// Convert2AWS returns the RDS instance of the database. Instances are
+// encrypted and deleted without a final snapshot, keeping the data is left
+// to the deletion policy.
+func (d *Database) Convert2AWS(providerConfig string) *awsrdsv1.Instance {
+	data := d.Spec.ForProvider
+	storage := float64(data.StorageGB)
+	enabled, skipSnapshot := true, true
+	return &awsrdsv1.Instance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: awsrdsv1.InstanceSpec{
+			ForProvider: awsrdsv1.InstanceParameters{
+				AllocatedStorage:  &storage,
+				Engine:            utils.StrPtr(data.Engine),
+				EngineVersion:     utils.StrPtr(data.Version),
+				InstanceClass:     utils.StrPtr(awsInstanceClasses[data.Size]),
+				MultiAz:           &data.HighAvailability,
+				Region:            utils.StrPtr(data.Region),
+				Username:          utils.StrPtr(data.Username),
+				PasswordSecretRef: d.passwordRef(),
+				SkipFinalSnapshot: &skipSnapshot,
+				StorageEncrypted:  &enabled,
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{
+					Name: providerConfig,
+				},
+			},
+		},
+	}
+}
+
+// Convert2Azure returns the resource group and the postgres flexible server
+// of the database. Highly available servers keep their standby in another
+// zone.
+func (d *Database) Convert2Azure(providerConfig string) (*azurev1.ResourceGroup, *azuredbv1.FlexibleServer) {
+	data := d.Spec.ForProvider
+	providerConfigRef := &xpv1.Reference{
+		Name: providerConfig,
+	}
+	resourceGroup := &azurev1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: utils.AzureResourceGroupName("Database", data.Name),
+		},
+		Spec: azurev1.ResourceGroupSpec{
+			ForProvider: azurev1.ResourceGroupParameters{
+				Location: utils.StrPtr(data.Region),
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	storage := float64(data.StorageGB * 1024)
+	var highAvailability []azuredbv1.HighAvailabilityParameters
+	if data.HighAvailability {
+		highAvailability = append(highAvailability, azuredbv1.HighAvailabilityParameters{Mode: utils.StrPtr("ZoneRedundant")})
+	}
+	server := &azuredbv1.FlexibleServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: azuredbv1.FlexibleServerSpec{
+			ForProvider: azuredbv1.FlexibleServerParameters{
+				AdministratorLogin:             utils.StrPtr(data.Username),
+				AdministratorPasswordSecretRef: d.passwordRef(),
+				HighAvailability:               highAvailability,
+				Location:                       utils.StrPtr(data.Region),
+				ResourceGroupNameRef:           &xpv1.Reference{Name: resourceGroup.GetName()},
+				SkuName:                        utils.StrPtr(azureSkus[data.Size]),
+				StorageMb:                      &storage,
+				Version:                        utils.StrPtr(data.Version),
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	return resourceGroup, server
+}
+
+// ObserveGCP fills the status with the address of the observed instance. The
+// database is ready once its administrator user exists as well.
+func (d *Database) ObserveGCP(instance *gcpsqlv1.DatabaseInstance, user *gcpsqlv1.User) {
+	d.Status.Endpoint = utils.StrValue(instance.Status.AtProvider.FirstIPAddress)
+	d.Status.Port = enginePorts[d.Spec.ForProvider.Engine]
+	utils.MirrorLeastReady(&d.Status.ConditionedStatus, instance, user)
+}
+
+func (d *Database) ObserveAWS(instance *awsrdsv1.Instance) {
+	d.Status.Endpoint = utils.StrValue(instance.Status.AtProvider.Address)
+	d.Status.Port = enginePorts[d.Spec.ForProvider.Engine]
+	if port := instance.Status.AtProvider.Port; port != nil {
+		d.Status.Port = int(*port)
+	}
+	utils.MirrorConditions(&d.Status.ConditionedStatus, instance)
+}
+
+func (d *Database) ObserveAzure(server *azuredbv1.FlexibleServer) {
+	d.Status.Endpoint = utils.StrValue(server.Status.AtProvider.Fqdn)
+	d.Status.Port = enginePorts[d.Spec.ForProvider.Engine]
+	utils.MirrorConditions(&d.Status.ConditionedStatus, server)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -34,14 +35,33 @@ type PackageConfig struct {
 
 type MissionSpec struct {
 	Packages []PackageConfig `json:"packages,omitempty"`
+	// DeletionPolicy is used by the resources of this Mission that do not
+	// set their own. Orphan keeps the cloud resources once they are deleted.
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	// +kubebuilder:default=Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// PackageStatus reports the state of a single package of the Mission.
+type PackageStatus struct {
+	Provider              string `json:"provider,omitempty"`
+	CRDFound              bool   `json:"crdFound"`
+	ProviderConfigCreated bool   `json:"providerConfigCreated"`
+	MissionKeyFound       bool   `json:"missionKeyFound"`
 }
 
 type MissionStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	ObservedGeneration     int64           `json:"observedGeneration,omitempty"`
+	Packages               []PackageStatus `json:"packages,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 type Mission struct {
 	metav1.TypeMeta   `json:",inline"`
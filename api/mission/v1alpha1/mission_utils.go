@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -25,10 +26,32 @@ import (
 	azrv1 "github.com/upbound/provider-azure/apis/v1beta1"
 	gcpv1 "github.com/upbound/provider-gcp/apis/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
-func (m *Mission) Convert2GCP(pkg *PackageConfig) *gcpv1.ProviderConfig {
-	providerName := m.Name + "-" + strings.ToLower(pkg.Provider)
+// Namespace searched for MissionKeys when a package does not name one.
+const DefaultCredentialsNamespace = "default"
+
+// Credential sources of the AWS and Azure providers for workload identity,
+// GCP uses the injected identity of crossplane-runtime.
+const (
+	CredentialsSourceIRSA          xpv1.CredentialsSource = "IRSA"
+	CredentialsSourceOIDCTokenFile xpv1.CredentialsSource = "OIDCTokenFile"
+)
+
+// ProviderConfigName is the name of the ProviderConfig created for a
+// provider of the named mission, and referenced by every managed resource.
+func ProviderConfigName(missionName, provider string) string {
+	return missionName + "-" + strings.ToLower(provider)
+}
+
+func (m *Mission) ProviderConfigName(pkg *PackageConfig) string {
+	return ProviderConfigName(m.GetName(), pkg.Provider)
+}
+
+func (m *Mission) Convert2GCP(pkg *PackageConfig, key *MissionKey) *gcpv1.ProviderConfig {
+	providerName := m.ProviderConfigName(pkg)
+	source, selectors := credentials(pkg, key)
 	providerConfig := &gcpv1.ProviderConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ProviderConfig",
@@ -40,70 +63,114 @@ func (m *Mission) Convert2GCP(pkg *PackageConfig) *gcpv1.ProviderConfig {
 		Spec: gcpv1.ProviderConfigSpec{
 			ProjectID: pkg.ProjectID,
 			Credentials: gcpv1.ProviderCredentials{
-				Source: xpv1.CredentialsSourceSecret,
-				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
-					SecretRef: &xpv1.SecretKeySelector{
-						Key: pkg.Credentials.Key,
-						SecretReference: xpv1.SecretReference{
-							Name:      pkg.Credentials.Name,
-							Namespace: pkg.Credentials.Namespace,
-						},
-					},
-				},
+				Source:                    source,
+				CommonCredentialSelectors: selectors,
 			},
 		},
 	}
+	if key.UsesIdentity() {
+		providerConfig.Spec.Credentials = gcpv1.ProviderCredentials{Source: xpv1.CredentialsSourceInjectedIdentity}
+	}
 	return providerConfig
 }
 
-func (m *Mission) Convert2AWS(pkg *PackageConfig) *awsv1.ProviderConfig {
-	providerName := m.Name + "-" + strings.ToLower(pkg.Provider)
+func (m *Mission) Convert2AWS(pkg *PackageConfig, key *MissionKey) *awsv1.ProviderConfig {
+	providerName := m.ProviderConfigName(pkg)
+	source, selectors := credentials(pkg, key)
 	providerConfig := &awsv1.ProviderConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: providerName,
 		},
 		Spec: awsv1.ProviderConfigSpec{
 			Credentials: awsv1.ProviderCredentials{
-				Source: xpv1.CredentialsSourceSecret,
-				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
-					SecretRef: &xpv1.SecretKeySelector{
-						Key: pkg.Credentials.Key,
-						SecretReference: xpv1.SecretReference{
-							Name:      pkg.Credentials.Name,
-							Namespace: pkg.Credentials.Namespace,
-						},
-					},
-				},
+				Source:                    source,
+				CommonCredentialSelectors: selectors,
 			},
 		},
 	}
+	if key.UsesIdentity() {
+		providerConfig.Spec.Credentials = awsv1.ProviderCredentials{Source: CredentialsSourceIRSA}
+	}
 	return providerConfig
 }
 
-func (m *Mission) Convert2Azure(pkg *PackageConfig) *azrv1.ProviderConfig {
-	providerName := m.Name + "-" + strings.ToLower(pkg.Provider)
+func (m *Mission) Convert2Azure(pkg *PackageConfig, key *MissionKey) *azrv1.ProviderConfig {
+	providerName := m.ProviderConfigName(pkg)
+	source, selectors := credentials(pkg, key)
 	providerConfig := &azrv1.ProviderConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: providerName,
 		},
 		Spec: azrv1.ProviderConfigSpec{
 			Credentials: azrv1.ProviderCredentials{
-				Source: xpv1.CredentialsSourceSecret,
-				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
-					SecretRef: &xpv1.SecretKeySelector{
-						Key: pkg.Credentials.Key,
-						SecretReference: xpv1.SecretReference{
-							Name:      pkg.Credentials.Name,
-							Namespace: pkg.Credentials.Namespace,
-						},
-					},
-				},
+				Source:                    source,
+				CommonCredentialSelectors: selectors,
 			},
 		},
 	}
+	if key.UsesIdentity() {
+		providerConfig.Spec.Credentials = azrv1.ProviderCredentials{Source: CredentialsSourceOIDCTokenFile}
+		providerConfig.Spec.ClientID = &key.Spec.Identity.ID
+		providerConfig.Spec.TenantID = &key.Spec.Identity.TenantID
+		providerConfig.Spec.SubscriptionID = &key.Spec.Identity.SubscriptionID
+	}
 	return providerConfig
 }
 
+// credentials returns where the provider of a package reads its credentials
+// from: the Secret of the MissionKey, unless the key names another source.
+func credentials(pkg *PackageConfig, key *MissionKey) (xpv1.CredentialsSource, xpv1.CommonCredentialSelectors) {
+	if key != nil && key.Spec.Source != "" {
+		return key.Spec.Source, *key.Spec.CommonCredentialSelectors.DeepCopy()
+	}
+	ref := pkg.KeyRef()
+	return xpv1.CredentialsSourceSecret, xpv1.CommonCredentialSelectors{
+		SecretRef: &xpv1.SecretKeySelector{
+			Key: pkg.Credentials.Key,
+			SecretReference: xpv1.SecretReference{
+				Name:      ref.Name,
+				Namespace: ref.Namespace,
+			},
+		},
+	}
+}
+
+// GetPackage finds the package a resource should be created with. Provider
+// and keyName narrow the search and may be left empty, but exactly one
+// package has to match.
+func (m *Mission) GetPackage(provider, keyName string) (*PackageConfig, error) {
+	var matches []*PackageConfig
+	for i := range m.Spec.Packages {
+		pkg := &m.Spec.Packages[i]
+		if provider != "" && !strings.EqualFold(pkg.Provider, provider) {
+			continue
+		}
+		if keyName != "" && pkg.Credentials.Name != keyName {
+			continue
+		}
+		matches = append(matches, pkg)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("mission %s has no package with provider %q and key %q", m.GetName(), provider, keyName)
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("mission %s has %d packages matching provider %q and key %q, please set the provider and key name explicitly", m.GetName(), len(matches), provider, keyName)
+	}
+	return matches[0], nil
+}
+
+// KeyRef is the MissionKey a package reads its credentials from. Missions
+// are cluster scoped while MissionKeys are namespaced, so the namespace is
+// always explicit and DefaultCredentialsNamespace when the package does not
+// name one.
+func (p *PackageConfig) KeyRef() types.NamespacedName {
+	namespace := p.Credentials.Namespace
+	if namespace == "" {
+		namespace = DefaultCredentialsNamespace
+	}
+	return types.NamespacedName{Name: p.Credentials.Name, Namespace: namespace}
+}
+
 func (m *Mission) GCPVerify(packageId int) error {
 	pkg := m.Spec.Packages[packageId]
 	if pkg.ProjectID == "" {
@@ -17,20 +17,121 @@ limitations under the License.
 package v1alpha1
 
 import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type MissionKeySpec struct {
-	Name string `json:"name,omitempty"`
-	Type string `json:"type,omitempty"`
+	// AllowedMissions names the Missions that may use this key, or "*" for
+	// every Mission. Missions are cluster scoped and could otherwise
+	// reference keys of any namespace. When the list is empty only keys in
+	// the default namespace, where Missions look for keys, may be used.
+	// +optional
+	AllowedMissions []string `json:"allowedMissions,omitempty"`
+	Name            string   `json:"name,omitempty"`
+	Type            string   `json:"type,omitempty"`
+	// Data holds the credentials in the format of the provider: a GCP
+	// service account JSON key, an AWS shared credentials file or an Azure
+	// SDK auth JSON file.
 	Data []byte `json:"data,omitempty"`
+	// Source of the credentials when they are not kept in Data: Secret
+	// reads a key of an existing Secret in the namespace of the MissionKey,
+	// Filesystem a file and Environment a variable of the provider pods.
+	// +kubebuilder:validation:Enum=Secret;Filesystem;Environment
+	// +optional
+	Source                         xpv1.CredentialsSource `json:"source,omitempty"`
+	xpv1.CommonCredentialSelectors `json:",inline"`
+	// Encryption is set when Data is envelope encrypted, only the operator
+	// holds the key to decrypt it.
+	// +optional
+	Encryption *DataEncryption `json:"encryption,omitempty"`
+	// Rotation sets when the credentials expire and how long replaced
+	// credentials are kept.
+	// +optional
+	Rotation *RotationPolicy `json:"rotation,omitempty"`
+	// Identity binds the ServiceAccount of the key to a cloud identity so
+	// providers authenticate through workload identity instead of Data.
+	// Provider packages installed by the operator run with the identity of
+	// one key of their provider only.
+	// +optional
+	Identity *WorkloadIdentity `json:"identity,omitempty"`
 }
 
+// DataEncryption describes how the Data of a MissionKey was encrypted: with
+// a random AES-256-GCM data key, itself encrypted by a key provider of the
+// operator.
+type DataEncryption struct {
+	// Provider of the operator that decrypts EncryptedKey, such as "local".
+	Provider string `json:"provider"`
+	// EncryptedKey is the data key, encrypted by the provider.
+	EncryptedKey []byte `json:"encryptedKey"`
+}
+
+// RotationPolicy tracks the lifetime of the credentials of a MissionKey.
+type RotationPolicy struct {
+	// ExpiresAt is when the credentials stop working. Expired keys are no
+	// longer synced.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// WarnBefore is how long before ExpiresAt warnings are emitted, 168h
+	// when unset.
+	// +optional
+	WarnBefore *metav1.Duration `json:"warnBefore,omitempty"`
+	// Overlap is how long replaced credentials are kept in the
+	// "<name>-previous" Secret after new ones are supplied. Replaced
+	// credentials are dropped at once when unset.
+	// +optional
+	Overlap *metav1.Duration `json:"overlap,omitempty"`
+}
+
+// WorkloadIdentity is a cloud identity that Kubernetes ServiceAccounts can
+// impersonate: GKE Workload Identity, EKS IAM roles for service accounts or
+// Azure Workload Identity.
+type WorkloadIdentity struct {
+	// ID of the identity: a GCP service account email, an AWS IAM role ARN
+	// or the client id of an Azure managed identity or application.
+	ID string `json:"id"`
+	// TenantID of the Azure identity, required for Azure.
+	// +optional
+	TenantID string `json:"tenantId,omitempty"`
+	// SubscriptionID the Azure provider manages resources in, required for
+	// Azure.
+	// +optional
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+}
+
+// Conditions reported by MissionKeys besides Ready and Synced.
+const (
+	TypeSecretSynced         xpv1.ConditionType = "SecretSynced"
+	TypeServiceAccountSynced xpv1.ConditionType = "ServiceAccountSynced"
+	TypeCredentialsValid     xpv1.ConditionType = "CredentialsValid"
+	TypeCredentialsCurrent   xpv1.ConditionType = "CredentialsCurrent"
+)
+
 type MissionKeyStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	ObservedGeneration     int64 `json:"observedGeneration,omitempty"`
+	// Fingerprint is a hash of the version of the key, or of the Secret its
+	// credentials are read from, it changes whenever the key is rotated.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Missions lists the Missions with a package that uses this key.
+	Missions []string `json:"missions,omitempty"`
+	// RotatedAt is when the current credentials replaced the ones kept in
+	// the previous Secret.
+	RotatedAt *metav1.Time `json:"rotatedAt,omitempty"`
+	// PreviousSecret names the Secret keeping the replaced credentials
+	// until the overlap window ends.
+	PreviousSecret string `json:"previousSecret,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="VALID",type="string",JSONPath=".status.conditions[?(@.type=='CredentialsValid')].status"
+// +kubebuilder:printcolumn:name="EXPIRES",type="date",JSONPath=".spec.rotation.expiresAt"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 type MissionKey struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -17,18 +17,80 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cpv1alpha1 "github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
+// Key under which the MissionKey data is stored in its Secret.
+const DefaultCredentialsKey = "creds"
+
+// Annotations binding a ServiceAccount to the cloud identity of a
+// MissionKey, by provider type.
+var IdentityAnnotations = map[string]string{
+	"gcp":   "iam.gke.io/gcp-service-account",
+	"aws":   "eks.amazonaws.com/role-arn",
+	"azure": "azure.workload.identity/client-id",
+}
+
+// Annotation holding the tenant of an Azure workload identity.
+const AzureTenantAnnotation = "azure.workload.identity/tenant-id"
+
+// Label letting Azure Workload Identity inject credentials into a pod.
+const AzureIdentityLabel = "azure.workload.identity/use"
+
+// Annotation naming the MissionKey, as namespace/name, whose identity a
+// ControllerConfig runs provider packages with.
+const IdentityKeyAnnotation = "mission-control.apis.io/identity-key"
+
+// How long before expiry MissionKeys warn when their rotation policy does
+// not say.
+const DefaultExpiryWarning = 7 * 24 * time.Hour
+
+// UsesIdentity reports whether the key authenticates through workload
+// identity rather than stored credentials.
+func (k *MissionKey) UsesIdentity() bool {
+	return k != nil && k.Spec.Identity != nil
+}
+
+// StoresData reports whether the credentials are kept in the key itself and
+// copied into a Secret owned by the key.
+func (k *MissionKey) StoresData() bool {
+	return !k.UsesIdentity() && k.Spec.Source == ""
+}
+
+// HasPlainData reports whether Data holds the credentials in clear.
+func (k *MissionKey) HasPlainData() bool {
+	return k.StoresData() && k.Spec.Encryption == nil
+}
+
+// WithData returns a copy of the key holding data inline and in clear, so
+// credentials read from another source or decrypted can be verified like
+// inline ones. The copy must never be written back to the cluster.
+func (k *MissionKey) WithData(data []byte) *MissionKey {
+	key := k.DeepCopy()
+	key.Spec.Source = ""
+	key.Spec.CommonCredentialSelectors = xpv1.CommonCredentialSelectors{}
+	key.Spec.Encryption = nil
+	key.Spec.Data = data
+	return key
+}
+
 func (k *MissionKey) Convert2Secret() *v1.Secret {
 	return &v1.Secret{
-		Data: map[string][]byte{"creds": k.Spec.Data},
+		Data: map[string][]byte{DefaultCredentialsKey: k.Spec.Data},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      k.GetName(),
 			Namespace: k.GetNamespace(),
@@ -36,32 +98,332 @@ func (k *MissionKey) Convert2Secret() *v1.Secret {
 	}
 }
 
+// Convert2ServiceAccount returns the ServiceAccount of the key, annotated
+// with its cloud identity when the key uses workload identity.
 func (k *MissionKey) Convert2ServiceAccount() *v1.ServiceAccount {
-	return &v1.ServiceAccount{
+	serviceAccount := &v1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      k.GetName(),
 			Namespace: k.GetNamespace(),
 		},
 	}
+	if !k.UsesIdentity() {
+		return serviceAccount
+	}
+	if annotation, ok := IdentityAnnotations[k.Spec.Type]; ok {
+		metav1.SetMetaDataAnnotation(&serviceAccount.ObjectMeta, annotation, k.Spec.Identity.ID)
+	}
+	if k.Spec.Type == "azure" && k.Spec.Identity.TenantID != "" {
+		metav1.SetMetaDataAnnotation(&serviceAccount.ObjectMeta, AzureTenantAnnotation, k.Spec.Identity.TenantID)
+	}
+	return serviceAccount
 }
 
+// Convert2ControllerConfig returns the named ControllerConfig running
+// provider packages with the cloud identity of the key. Crossplane copies its
+// annotations onto the ServiceAccount of the provider pods, which are thus
+// annotated like the ServiceAccount of the key.
+func (k *MissionKey) Convert2ControllerConfig(name string) *cpv1alpha1.ControllerConfig {
+	serviceAccount := name
+	config := &cpv1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: k.Convert2ServiceAccount().GetAnnotations(),
+		},
+		Spec: cpv1alpha1.ControllerConfigSpec{
+			ServiceAccountName: &serviceAccount,
+		},
+	}
+	metav1.SetMetaDataAnnotation(&config.ObjectMeta, IdentityKeyAnnotation, k.GetNamespace()+"/"+k.GetName())
+	if k.Spec.Type == "azure" {
+		config.Spec.Metadata = &cpv1alpha1.PodObjectMeta{Labels: map[string]string{AzureIdentityLabel: "true"}}
+	}
+	return config
+}
+
+// GCPVerify expects a service account key file as downloaded from the
+// console, or a service account email as identity.
 func (k *MissionKey) GCPVerify() error {
-	return k.GenericVerify()
+	if err := k.GenericVerify(); err != nil {
+		return err
+	}
+	if k.UsesIdentity() {
+		if !strings.HasSuffix(k.Spec.Identity.ID, ".gserviceaccount.com") {
+			return errors.New("GCP identity must be a service account email ending in .gserviceaccount.com.")
+		}
+		return nil
+	}
+	if !k.HasPlainData() {
+		// Credentials from other sources or encrypted ones are checked
+		// where they are read.
+		return nil
+	}
+	creds, err := jsonFields(k.Spec.Data)
+	if err != nil {
+		return errors.New("GCP key is not a valid service account JSON file.")
+	}
+	if creds["type"] != "service_account" {
+		return errors.New("GCP key must be of type \"service_account\".")
+	}
+	return missingFields("GCP", creds, "project_id", "private_key_id", "private_key", "client_email")
 }
 
+// AWSVerify expects a shared credentials file with at least one profile, or
+// an IAM role ARN as identity.
 func (k *MissionKey) AWSVerify() error {
-	return k.GenericVerify()
+	if err := k.GenericVerify(); err != nil {
+		return err
+	}
+	if k.UsesIdentity() {
+		if !strings.HasPrefix(k.Spec.Identity.ID, "arn:") || !strings.Contains(k.Spec.Identity.ID, ":role/") {
+			return errors.New("AWS identity must be an IAM role ARN.")
+		}
+		return nil
+	}
+	if !k.HasPlainData() {
+		// Credentials from other sources or encrypted ones are checked
+		// where they are read.
+		return nil
+	}
+	profiles := parseINI(k.Spec.Data)
+	if len(profiles) == 0 {
+		return errors.New("AWS key is not a credentials file, expected a profile such as [default].")
+	}
+	for profile, creds := range profiles {
+		if err := missingFields("AWS profile "+profile, creds, "aws_access_key_id", "aws_secret_access_key"); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// AzureVerify expects the JSON file produced by
+// "az ad sp create-for-rbac --sdk-auth", or a client, tenant and
+// subscription id as identity.
 func (k *MissionKey) AzureVerify() error {
-	return k.GenericVerify()
+	if err := k.GenericVerify(); err != nil {
+		return err
+	}
+	if k.UsesIdentity() {
+		identity := map[string]string{
+			"id":             k.Spec.Identity.ID,
+			"tenantId":       k.Spec.Identity.TenantID,
+			"subscriptionId": k.Spec.Identity.SubscriptionID,
+		}
+		return missingFields("Azure identity", identity, "id", "tenantId", "subscriptionId")
+	}
+	if !k.HasPlainData() {
+		// Credentials from other sources or encrypted ones are checked
+		// where they are read.
+		return nil
+	}
+	creds, err := jsonFields(k.Spec.Data)
+	if err != nil {
+		return errors.New("Azure key is not a valid SDK auth JSON file.")
+	}
+	return missingFields("Azure", creds, "clientId", "tenantId", "subscriptionId")
 }
 
+// GenericVerify checks the parts of the key every provider shares. Whether
+// the provider type is supported is up to the caller, which resolves the
+// provider before verifying the key with it.
 func (k *MissionKey) GenericVerify() error {
-	if !utils.Contains(utils.GetSupportedProviders(), k.Spec.Type) {
-		message := fmt.Sprintf("Key of provider type %s is not supported, please use one of %v", k.Spec.Type, utils.GetSupportedProviders())
-		err := errors.New(message)
-		return err
+	if k.UsesIdentity() && len(k.Spec.Data) != 0 {
+		return errors.New("Key data must be empty when an identity is set.")
+	}
+	if k.OverlapWindow() < 0 || k.ExpiryWarning() < 0 {
+		return errors.New("Key rotation durations must not be negative.")
+	}
+	if k.Spec.Encryption != nil {
+		if k.UsesIdentity() || k.Spec.Source != "" {
+			return errors.New("Key encryption only applies to data, not to an identity or source.")
+		}
+		if k.Spec.Encryption.Provider == "" || len(k.Spec.Encryption.EncryptedKey) == 0 || len(k.Spec.Data) == 0 {
+			return errors.New("Encrypted keys need data, a provider and an encrypted data key.")
+		}
+	}
+	if k.Spec.Source == "" {
+		return nil
+	}
+	if k.UsesIdentity() || len(k.Spec.Data) != 0 {
+		return errors.New("Key data and identity must be empty when a source is set.")
+	}
+	switch k.Spec.Source {
+	case xpv1.CredentialsSourceSecret:
+		ref := k.Spec.SecretRef
+		if ref == nil || ref.Name == "" || ref.Key == "" {
+			return errors.New("Key source Secret needs a secretRef with a name and key.")
+		}
+		if ref.Namespace != k.GetNamespace() {
+			return errors.New("Key secretRef must be in the namespace of the MissionKey.")
+		}
+	case xpv1.CredentialsSourceFilesystem:
+		if k.Spec.Fs == nil || k.Spec.Fs.Path == "" {
+			return errors.New("Key source Filesystem needs an fs path.")
+		}
+	case xpv1.CredentialsSourceEnvironment:
+		if k.Spec.Env == nil || k.Spec.Env.Name == "" {
+			return errors.New("Key source Environment needs an env name.")
+		}
+	default:
+		return fmt.Errorf("Key source %s is not supported, please use Secret, Filesystem or Environment.", k.Spec.Source)
+	}
+	return nil
+}
+
+// Fingerprint identifies the current version of the key. It hashes the UID
+// and generation of the key rather than its data, so nothing can be learnt
+// about the credentials from it.
+func (k *MissionKey) Fingerprint() string {
+	return fingerprint(k.GetUID(), strconv.FormatInt(k.GetGeneration(), 10))
+}
+
+// SecretFingerprint identifies the current version of the Secret a key with
+// a Secret source reads its credentials from.
+func SecretFingerprint(secret *v1.Secret) string {
+	return fingerprint(secret.GetUID(), secret.GetResourceVersion())
+}
+
+func fingerprint(uid types.UID, version string) string {
+	sum := sha256.Sum256([]byte(string(uid) + "/" + version))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// AllowAllMissions in AllowedMissions lets every Mission use the key.
+const AllowAllMissions = "*"
+
+// AllowsMission reports whether the named Mission may use the key. Keys
+// outside the default namespace have to allow Missions explicitly.
+func (k *MissionKey) AllowsMission(missionName string) bool {
+	if len(k.Spec.AllowedMissions) == 0 {
+		return k.GetNamespace() == DefaultCredentialsNamespace
+	}
+	return slices.Contains(k.Spec.AllowedMissions, AllowAllMissions) || slices.Contains(k.Spec.AllowedMissions, missionName)
+}
+
+// PreviousExpiresAnnotation records on the previous Secret when the
+// overlap window ends and the Secret is deleted.
+const PreviousExpiresAnnotation = "mission-control.apis.io/expires-at"
+
+// PreviousSecretName is the Secret keeping replaced credentials during the
+// overlap window.
+func (k *MissionKey) PreviousSecretName() string {
+	return k.GetName() + "-previous"
+}
+
+// OverlapWindow is how long replaced credentials are kept.
+func (k *MissionKey) OverlapWindow() time.Duration {
+	if k.Spec.Rotation == nil || k.Spec.Rotation.Overlap == nil {
+		return 0
+	}
+	return k.Spec.Rotation.Overlap.Duration
+}
+
+// ExpiryWarning is how long before expiry the key starts warning.
+func (k *MissionKey) ExpiryWarning() time.Duration {
+	if k.Spec.Rotation == nil || k.Spec.Rotation.WarnBefore == nil {
+		return DefaultExpiryWarning
+	}
+	return k.Spec.Rotation.WarnBefore.Duration
+}
+
+// ExpiresIn returns how long the credentials remain valid at now, negative
+// once expired. It returns false if the key does not expire.
+func (k *MissionKey) ExpiresIn(now time.Time) (time.Duration, bool) {
+	if k.Spec.Rotation == nil || k.Spec.Rotation.ExpiresAt == nil {
+		return 0, false
+	}
+	return k.Spec.Rotation.ExpiresAt.Sub(now), true
+}
+
+// Expired reports whether the credentials have expired at now.
+func (k *MissionKey) Expired(now time.Time) bool {
+	remaining, expires := k.ExpiresIn(now)
+	return expires && remaining <= 0
+}
+
+// ExpiryCondition reports whether the credentials are current at now. Keys
+// about to expire stay True with reason Expiring, expired keys are False.
+func (k *MissionKey) ExpiryCondition(now time.Time) xpv1.Condition {
+	condition := KeyCondition(TypeCredentialsCurrent, nil)
+	remaining, expires := k.ExpiresIn(now)
+	switch {
+	case !expires:
+		condition.Reason = "NoExpiry"
+	case remaining <= 0:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = "Expired"
+		condition.Message = fmt.Sprintf("Credentials expired at %s, please supply new ones.", k.Spec.Rotation.ExpiresAt.UTC().Format(time.RFC3339))
+	case remaining <= k.ExpiryWarning():
+		condition.Reason = "Expiring"
+		condition.Message = fmt.Sprintf("Credentials expire at %s.", k.Spec.Rotation.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return condition
+}
+
+// KeyCondition returns a condition of type t that is True when err is nil,
+// and False with the error as message otherwise.
+func KeyCondition(t xpv1.ConditionType, err error) xpv1.Condition {
+	condition := xpv1.Condition{
+		Type:               t,
+		Status:             v1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "Success",
+	}
+	if err != nil {
+		condition.Status = v1.ConditionFalse
+		condition.Reason = "Failed"
+		condition.Message = err.Error()
+	}
+	return condition
+}
+
+// missingFields returns an error naming the fields that are absent or empty.
+// Values are never part of the error as they are secret.
+func missingFields(kind string, creds map[string]string, fields ...string) error {
+	var missing []string
+	for _, field := range fields {
+		if creds[field] == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("%s key is missing %s.", kind, strings.Join(missing, ", "))
 	}
 	return nil
 }
+
+// jsonFields returns the string fields of a JSON object.
+func jsonFields(data []byte) (map[string]string, error) {
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	for key, value := range object {
+		if s, ok := value.(string); ok {
+			fields[key] = s
+		}
+	}
+	return fields, nil
+}
+
+// parseINI reads the key value pairs of every section of an INI file.
+func parseINI(data []byte) map[string]map[string]string {
+	sections := map[string]map[string]string{}
+	var current map[string]string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = map[string]string{}
+			sections[strings.TrimSpace(line[1:len(line)-1])] = current
+		case current != nil:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return sections
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Subnet is a range of the network addresses in a single region. Subnets of
+// a network do not overlap.
+type Subnet struct {
+	Name string `json:"name"`
+	CIDR string `json:"cidr"`
+	// Region the subnet is created in, the region of the network when
+	// empty. On AWS it is an availability zone of the network region and
+	// Azure subnets always share the region of the network.
+	Region string `json:"region,omitempty"`
+}
+
+// FirewallRule allows inbound traffic into the network. Outbound traffic is
+// always allowed.
+type FirewallRule struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=tcp;udp;icmp;all
+	Protocol string `json:"protocol"`
+	// Ports are single ports or ranges such as "8000-8080". Every port is
+	// allowed when empty.
+	Ports []string `json:"ports,omitempty"`
+	// SourceRanges are the CIDRs allowed to connect, any address when empty.
+	SourceRanges []string `json:"sourceRanges,omitempty"`
+}
+
+type ProviderData struct {
+	Name string `json:"name,omitempty"`
+	// Region of the network. GCP networks are global and only use the
+	// regions of their subnets.
+	Region string `json:"region,omitempty"`
+	// CIDR is the address space of the network. It is required on AWS and
+	// Azure, GCP networks only have the ranges of their subnets.
+	CIDR          string         `json:"cidr,omitempty"`
+	Subnets       []Subnet       `json:"subnets,omitempty"`
+	FirewallRules []FirewallRule `json:"firewallRules,omitempty"`
+}
+
+type NetworkMissionRef struct {
+	MissionName string `json:"missionName,omitempty"`
+	MissionKey  string `json:"keyName,omitempty"`
+}
+
+type NetworkSpec struct {
+	MissionRef  NetworkMissionRef `json:"missionRef,omitempty"`
+	ForProvider ProviderData      `json:"forProvider,omitempty"`
+	// Provider selects the Mission package to create the network with. It is
+	// only needed when the mission key alone does not identify the package.
+	Provider string `json:"provider,omitempty"`
+	// DeletionPolicy decides whether the cloud resources are deleted or
+	// orphaned along with the Network. Defaults to the Mission policy.
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// NetworkStatus reports the ids the provider gave to the network and its
+// subnets.
+type NetworkStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	Provider               string `json:"provider,omitempty"`
+	NetworkID              string `json:"networkId,omitempty"`
+	// SubnetIDs maps the subnet names to their provider ids.
+	SubnetIDs map[string]string `json:"subnetIds,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+//+kubebuilder:printcolumn:name="PROVIDER",type="string",JSONPath=".status.provider"
+//+kubebuilder:printcolumn:name="CIDR",type="string",JSONPath=".spec.forProvider.cidr"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Network is the Schema for the networks API
+type Network struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkSpec   `json:"spec,omitempty"`
+	Status NetworkStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NetworkList contains a list of Network
+type NetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Network `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Network{}, &NetworkList{})
+}
@@ -0,0 +1,737 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	awscomputev1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	azurev1 "github.com/upbound/provider-azure/apis/azure/v1beta1"
+	azurenetworkv1 "github.com/upbound/provider-azure/apis/network/v1beta1"
+	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	validation "k8s.io/apimachinery/pkg/util/validation"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// Protocols accepted by firewall rules.
+const (
+	ProtocolTCP  = "tcp"
+	ProtocolUDP  = "udp"
+	ProtocolICMP = "icmp"
+	ProtocolAll  = "all"
+)
+
+// Source range used by firewall rules that do not restrict their sources.
+const anyAddress = "0.0.0.0/0"
+
+// AWSEgressRule names the security group rule letting all traffic out of an
+// AWS network. Security groups managed by Crossplane lose the egress rule
+// AWS creates them with, so the operator adds it back.
+const AWSEgressRule = "egress"
+
+// SubnetResourceName is the name of the managed resources created for a
+// subnet of the named network.
+func SubnetResourceName(network, subnet string) string {
+	return network + "-" + subnet
+}
+
+// RuleResourceName is the name of the managed resources created for a
+// firewall rule of the named network.
+func RuleResourceName(network, rule string) string {
+	return network + "-" + rule
+}
+
+// GetSubnet returns the named subnet, or the first subnet of the network
+// when no name is given.
+func (n *Network) GetSubnet(name string) (*Subnet, error) {
+	subnets := n.Spec.ForProvider.Subnets
+	if name == "" && len(subnets) > 0 {
+		return &subnets[0], nil
+	}
+	for i := range subnets {
+		if subnets[i].Name == name {
+			return &subnets[i], nil
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("Network %s has no subnets.", n.Name)
+	}
+	return nil, fmt.Errorf("Network %s has no subnet %s.", n.Name, name)
+}
+
+// ParsePorts parses a single port or a range of ports such as "8000-8080".
+func ParsePorts(ports string) (from, to int, err error) {
+	first, last, isRange := strings.Cut(ports, "-")
+	if from, err = strconv.Atoi(first); err != nil {
+		return 0, 0, fmt.Errorf("Invalid port %q.", ports)
+	}
+	to = from
+	if isRange {
+		if to, err = strconv.Atoi(last); err != nil {
+			return 0, 0, fmt.Errorf("Invalid port %q.", ports)
+		}
+	}
+	if from < 0 || to > 65535 || from > to {
+		return 0, 0, fmt.Errorf("Port range %q must lie within 0-65535 and start with its lowest port.", ports)
+	}
+	return from, to, nil
+}
+
+// GCP names networks, subnetworks and firewalls after RFC 1035.
+var gcpNetworkNamePattern = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// Azure rules are given increasing priorities from the lowest priority
+// number, the highest precedence, Azure accepts.
+const (
+	azureFirstPriority = 100
+	azureLastPriority  = 4096
+	azurePriorityStep  = 10
+)
+
+func (n *Network) GCPVerify() error {
+	if err := n.GenericVerify(); err != nil {
+		return err
+	}
+	data := n.Spec.ForProvider
+	names := []string{data.Name}
+	for _, subnet := range data.Subnets {
+		names = append(names, SubnetResourceName(data.Name, subnet.Name))
+		if subnet.Region == "" && data.Region == "" {
+			return fmt.Errorf("Subnet %s needs a region, GCP networks do not have one.", subnet.Name)
+		}
+	}
+	for _, rule := range data.FirewallRules {
+		names = append(names, RuleResourceName(data.Name, rule.Name))
+	}
+	for _, name := range names {
+		if !gcpNetworkNamePattern.MatchString(name) {
+			return fmt.Errorf("GCP network resource %s must start with a letter and have at most 63 lowercase letters, digits or hyphens.", name)
+		}
+	}
+	return nil
+}
+
+func (n *Network) AWSVerify() error {
+	if err := n.verifyRegional("AWS"); err != nil {
+		return err
+	}
+	data := n.Spec.ForProvider
+	for _, subnet := range data.Subnets {
+		if subnet.Region != "" && !strings.HasPrefix(subnet.Region, data.Region) {
+			return fmt.Errorf("Subnet %s must be in an availability zone of %s.", subnet.Name, data.Region)
+		}
+	}
+	return n.verifyAWSRuleNames()
+}
+
+func (n *Network) AzureVerify() error {
+	if err := n.verifyRegional("Azure"); err != nil {
+		return err
+	}
+	data := n.Spec.ForProvider
+	for _, subnet := range data.Subnets {
+		if subnet.Region != "" && subnet.Region != data.Region {
+			return fmt.Errorf("Subnet %s must be in the region of its virtual network.", subnet.Name)
+		}
+	}
+	maxRules := (azureLastPriority-azureFirstPriority)/azurePriorityStep + 1
+	if len(data.FirewallRules) > maxRules {
+		return fmt.Errorf("Azure networks can have at most %d firewall rules.", maxRules)
+	}
+	return nil
+}
+
+// verifyRegional checks the fields needed by providers whose networks have
+// a single region and address space.
+func (n *Network) verifyRegional(provider string) error {
+	if err := n.GenericVerify(); err != nil {
+		return err
+	}
+	if n.Spec.ForProvider.CIDR == "" {
+		return fmt.Errorf("%s networks need a CIDR.", provider)
+	}
+	if n.Spec.ForProvider.Region == "" {
+		return fmt.Errorf("%s networks need a region.", provider)
+	}
+	return nil
+}
+
+// verifyAWSRuleNames checks that the security group rules created on AWS,
+// one for every port range of a firewall rule, do not share a name.
+func (n *Network) verifyAWSRuleNames() error {
+	data := n.Spec.ForProvider
+	created := map[string]string{RuleResourceName(data.Name, AWSEgressRule): "the egress rule"}
+	for _, rule := range data.FirewallRules {
+		for _, name := range awsRuleNames(data.Name, rule) {
+			if other, ok := created[name]; ok {
+				return fmt.Errorf("Firewall rule %s creates the AWS security group rule %s, as %s does.", rule.Name, name, other)
+			}
+			if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+				return errors.New(strings.Join(errs, " "))
+			}
+			created[name] = "firewall rule " + rule.Name
+		}
+	}
+	return nil
+}
+
+// GenericVerify checks the names, address ranges and firewall rules of the
+// network in the way every provider expects them.
+func (n *Network) GenericVerify() error {
+	data := n.Spec.ForProvider
+	if data.Name == "" {
+		return errors.New("Network name not filled.")
+	}
+	if errs := validation.IsDNS1123Subdomain(data.Name); len(errs) != 0 {
+		return errors.New(strings.Join(errs, " "))
+	}
+	var network *net.IPNet
+	if data.CIDR != "" {
+		_, parsed, err := net.ParseCIDR(data.CIDR)
+		if err != nil {
+			return fmt.Errorf("Network CIDR %q is not valid.", data.CIDR)
+		}
+		network = parsed
+	}
+	subnets := map[string]bool{}
+	ranges := map[string]*net.IPNet{}
+	for _, subnet := range data.Subnets {
+		if err := verifyResourceName("Subnet", subnet.Name, SubnetResourceName(data.Name, subnet.Name), subnets); err != nil {
+			return err
+		}
+		_, subnetRange, err := net.ParseCIDR(subnet.CIDR)
+		if err != nil {
+			return fmt.Errorf("Subnet %s CIDR %q is not valid.", subnet.Name, subnet.CIDR)
+		}
+		if network != nil && !containsRange(network, subnetRange) {
+			return fmt.Errorf("Subnet %s CIDR %q is outside of the network CIDR %q.", subnet.Name, subnet.CIDR, data.CIDR)
+		}
+		for _, other := range data.Subnets {
+			otherRange, ok := ranges[other.Name]
+			if ok && (containsRange(otherRange, subnetRange) || containsRange(subnetRange, otherRange)) {
+				return fmt.Errorf("Subnet %s CIDR %q overlaps with subnet %s CIDR %q.", subnet.Name, subnet.CIDR, other.Name, other.CIDR)
+			}
+		}
+		ranges[subnet.Name] = subnetRange
+	}
+	rules := map[string]bool{}
+	for _, rule := range data.FirewallRules {
+		if err := verifyResourceName("Firewall rule", rule.Name, RuleResourceName(data.Name, rule.Name), rules); err != nil {
+			return err
+		}
+		if len(rule.Ports) > 0 && rule.Protocol != ProtocolTCP && rule.Protocol != ProtocolUDP {
+			return fmt.Errorf("Firewall rule %s can only list ports for tcp or udp.", rule.Name)
+		}
+		for _, ports := range rule.Ports {
+			if _, _, err := ParsePorts(ports); err != nil {
+				return err
+			}
+		}
+		for _, source := range rule.SourceRanges {
+			if _, _, err := net.ParseCIDR(source); err != nil {
+				return fmt.Errorf("Firewall rule %s source range %q is not a valid CIDR.", rule.Name, source)
+			}
+		}
+	}
+	return nil
+}
+
+// containsRange reports whether every address of inner is in outer. Two
+// address ranges either overlap in this way or not at all.
+func containsRange(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+// verifyResourceName checks that name is filled and unique among seen, and
+// that the name of the managed resources created for it is valid.
+func verifyResourceName(kind, name, resourceName string, seen map[string]bool) error {
+	if name == "" {
+		return fmt.Errorf("%s name not filled.", kind)
+	}
+	if seen[name] {
+		return fmt.Errorf("%s %s is defined more than once.", kind, name)
+	}
+	seen[name] = true
+	if errs := validation.IsDNS1123Subdomain(resourceName); len(errs) != 0 {
+		return errors.New(strings.Join(errs, " "))
+	}
+	return nil
+}
+
+// Convert2GCP returns the VPC network, its subnetworks and the firewalls
+// implementing its rules on GCP.
+func (n *Network) Convert2GCP(providerConfig string) (*gcpcomputev1.Network, []*gcpcomputev1.Subnetwork, []*gcpcomputev1.Firewall) {
+	data := n.Spec.ForProvider
+	providerConfigRef := &xpv1.Reference{
+		Name: providerConfig,
+	}
+	autoCreate := false
+	network := &gcpcomputev1.Network{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: gcpcomputev1.NetworkSpec{
+			ForProvider: gcpcomputev1.NetworkParameters{
+				AutoCreateSubnetworks: &autoCreate,
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	var subnetworks []*gcpcomputev1.Subnetwork
+	for _, subnet := range data.Subnets {
+		region := subnet.Region
+		if region == "" {
+			region = data.Region
+		}
+		subnetworks = append(subnetworks, &gcpcomputev1.Subnetwork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: SubnetResourceName(data.Name, subnet.Name),
+			},
+			Spec: gcpcomputev1.SubnetworkSpec{
+				ForProvider: gcpcomputev1.SubnetworkParameters{
+					IPCidrRange: utils.StrPtr(subnet.CIDR),
+					Region:      &region,
+					NetworkRef:  &xpv1.Reference{Name: network.GetName()},
+				},
+				ResourceSpec: xpv1.ResourceSpec{
+					ProviderConfigReference: providerConfigRef,
+				},
+			},
+		})
+	}
+	var firewalls []*gcpcomputev1.Firewall
+	for _, rule := range data.FirewallRules {
+		allow := gcpcomputev1.AllowParameters{
+			Protocol: utils.StrPtr(rule.Protocol),
+		}
+		for _, ports := range rule.Ports {
+			allow.Ports = append(allow.Ports, utils.StrPtr(ports))
+		}
+		var sources []*string
+		for _, source := range sourceRanges(rule) {
+			sources = append(sources, utils.StrPtr(source))
+		}
+		firewalls = append(firewalls, &gcpcomputev1.Firewall{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: RuleResourceName(data.Name, rule.Name),
+			},
+			Spec: gcpcomputev1.FirewallSpec{
+				ForProvider: gcpcomputev1.FirewallParameters{
+					Allow:        []gcpcomputev1.AllowParameters{allow},
+					Direction:    utils.StrPtr("INGRESS"),
+					SourceRanges: sources,
+					NetworkRef:   &xpv1.Reference{Name: network.GetName()},
+				},
+				ResourceSpec: xpv1.ResourceSpec{
+					ProviderConfigReference: providerConfigRef,
+				},
+			},
+		})
+	}
+	return network, subnetworks, firewalls
+}
+
+// Convert2AWS returns the VPC, its subnets and the security group holding
+// its rules on AWS. Every port range of a rule becomes a rule of its own,
+// and a last rule lets all traffic out of the network.
+func (n *Network) Convert2AWS(providerConfig string) (*awscomputev1.VPC, []*awscomputev1.Subnet, *awscomputev1.SecurityGroup, []*awscomputev1.SecurityGroupRule) {
+	data := n.Spec.ForProvider
+	providerConfigRef := &xpv1.Reference{
+		Name: providerConfig,
+	}
+	enableDNS := true
+	vpc := &awscomputev1.VPC{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: awscomputev1.VPCSpec{
+			ForProvider: awscomputev1.VPCParameters{
+				CidrBlock:          utils.StrPtr(data.CIDR),
+				EnableDNSHostnames: &enableDNS,
+				Region:             utils.StrPtr(data.Region),
+				Tags:               map[string]*string{"Name": utils.StrPtr(data.Name)},
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	var subnets []*awscomputev1.Subnet
+	for _, subnet := range data.Subnets {
+		name := SubnetResourceName(data.Name, subnet.Name)
+		params := awscomputev1.SubnetParameters{
+			CidrBlock: utils.StrPtr(subnet.CIDR),
+			Region:    utils.StrPtr(data.Region),
+			VPCIDRef:  &xpv1.Reference{Name: vpc.GetName()},
+			Tags:      map[string]*string{"Name": utils.StrPtr(name)},
+		}
+		if subnet.Region != "" && subnet.Region != data.Region {
+			params.AvailabilityZone = utils.StrPtr(subnet.Region)
+		}
+		subnets = append(subnets, &awscomputev1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: awscomputev1.SubnetSpec{
+				ForProvider: params,
+				ResourceSpec: xpv1.ResourceSpec{
+					ProviderConfigReference: providerConfigRef,
+				},
+			},
+		})
+	}
+	securityGroup := &awscomputev1.SecurityGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: awscomputev1.SecurityGroupSpec{
+			ForProvider: awscomputev1.SecurityGroupParameters{
+				Name:        utils.StrPtr(data.Name),
+				Description: utils.StrPtr("Firewall rules of network " + data.Name),
+				Region:      utils.StrPtr(data.Region),
+				VPCIDRef:    &xpv1.Reference{Name: vpc.GetName()},
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	var rules []*awscomputev1.SecurityGroupRule
+	for _, rule := range data.FirewallRules {
+		var cidrs []*string
+		for _, source := range sourceRanges(rule) {
+			cidrs = append(cidrs, utils.StrPtr(source))
+		}
+		names := awsRuleNames(data.Name, rule)
+		for i, ports := range awsPortRanges(rule) {
+			from, to := float64(ports[0]), float64(ports[1])
+			rules = append(rules, &awscomputev1.SecurityGroupRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: names[i],
+				},
+				Spec: awscomputev1.SecurityGroupRuleSpec{
+					ForProvider: awscomputev1.SecurityGroupRuleParameters{
+						Type:               utils.StrPtr("ingress"),
+						Protocol:           utils.StrPtr(awsProtocol(rule.Protocol)),
+						FromPort:           &from,
+						ToPort:             &to,
+						CidrBlocks:         cidrs,
+						Region:             utils.StrPtr(data.Region),
+						SecurityGroupIDRef: &xpv1.Reference{Name: securityGroup.GetName()},
+					},
+					ResourceSpec: xpv1.ResourceSpec{
+						ProviderConfigReference: providerConfigRef,
+					},
+				},
+			})
+		}
+	}
+	allPorts := float64(0)
+	rules = append(rules, &awscomputev1.SecurityGroupRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: RuleResourceName(data.Name, AWSEgressRule),
+		},
+		Spec: awscomputev1.SecurityGroupRuleSpec{
+			ForProvider: awscomputev1.SecurityGroupRuleParameters{
+				Type:               utils.StrPtr("egress"),
+				Protocol:           utils.StrPtr(awsProtocol(ProtocolAll)),
+				FromPort:           &allPorts,
+				ToPort:             &allPorts,
+				CidrBlocks:         []*string{utils.StrPtr(anyAddress)},
+				Region:             utils.StrPtr(data.Region),
+				SecurityGroupIDRef: &xpv1.Reference{Name: securityGroup.GetName()},
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	})
+	return vpc, subnets, securityGroup, rules
+}
+
+// awsRuleNames returns the names of the security group rules created for a
+// rule, numbered when it has several port ranges.
+func awsRuleNames(network string, rule FirewallRule) []string {
+	name := RuleResourceName(network, rule.Name)
+	ranges := awsPortRanges(rule)
+	if len(ranges) == 1 {
+		return []string{name}
+	}
+	names := make([]string, len(ranges))
+	for i := range ranges {
+		names[i] = name + "-" + strconv.Itoa(i)
+	}
+	return names
+}
+
+// awsProtocol returns the protocol name security group rules use.
+func awsProtocol(protocol string) string {
+	if protocol == ProtocolAll {
+		return "-1"
+	}
+	return protocol
+}
+
+// awsPortRanges returns the from and to ports of the security group rules
+// created for a rule. ICMP rules use -1 to allow every type and code.
+func awsPortRanges(rule FirewallRule) [][2]int {
+	switch {
+	case rule.Protocol == ProtocolICMP:
+		return [][2]int{{-1, -1}}
+	case rule.Protocol == ProtocolAll:
+		return [][2]int{{0, 0}}
+	case len(rule.Ports) == 0:
+		return [][2]int{{0, 65535}}
+	}
+	var ranges [][2]int
+	for _, ports := range rule.Ports {
+		from, to, _ := ParsePorts(ports)
+		ranges = append(ranges, [2]int{from, to})
+	}
+	return ranges
+}
+
+// Convert2Azure returns the resource group, virtual network, subnets and
+// network security group of a network on Azure, along with the security
+// rules and the associations of the group with every subnet. Rules get
+// increasing priorities in the order they are listed.
+func (n *Network) Convert2Azure(providerConfig string) (*azurev1.ResourceGroup, *azurenetworkv1.VirtualNetwork, []*azurenetworkv1.Subnet, *azurenetworkv1.SecurityGroup, []*azurenetworkv1.SecurityRule, []*azurenetworkv1.SubnetNetworkSecurityGroupAssociation) {
+	data := n.Spec.ForProvider
+	providerConfigRef := &xpv1.Reference{
+		Name: providerConfig,
+	}
+	resourceGroup := &azurev1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: utils.AzureResourceGroupName("Network", data.Name),
+		},
+		Spec: azurev1.ResourceGroupSpec{
+			ForProvider: azurev1.ResourceGroupParameters{
+				Location: utils.StrPtr(data.Region),
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	resourceGroupRef := &xpv1.Reference{Name: resourceGroup.GetName()}
+	virtualNetwork := &azurenetworkv1.VirtualNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: azurenetworkv1.VirtualNetworkSpec{
+			ForProvider: azurenetworkv1.VirtualNetworkParameters{
+				AddressSpace:         []*string{utils.StrPtr(data.CIDR)},
+				Location:             utils.StrPtr(data.Region),
+				ResourceGroupNameRef: resourceGroupRef,
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	securityGroup := &azurenetworkv1.SecurityGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: azurenetworkv1.SecurityGroupSpec{
+			ForProvider: azurenetworkv1.SecurityGroupParameters{
+				Location:             utils.StrPtr(data.Region),
+				ResourceGroupNameRef: resourceGroupRef,
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	var subnets []*azurenetworkv1.Subnet
+	var associations []*azurenetworkv1.SubnetNetworkSecurityGroupAssociation
+	for _, subnet := range data.Subnets {
+		name := SubnetResourceName(data.Name, subnet.Name)
+		subnets = append(subnets, &azurenetworkv1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: azurenetworkv1.SubnetSpec{
+				ForProvider: azurenetworkv1.SubnetParameters{
+					AddressPrefixes:       []*string{utils.StrPtr(subnet.CIDR)},
+					ResourceGroupNameRef:  resourceGroupRef,
+					VirtualNetworkNameRef: &xpv1.Reference{Name: virtualNetwork.GetName()},
+				},
+				ResourceSpec: xpv1.ResourceSpec{
+					ProviderConfigReference: providerConfigRef,
+				},
+			},
+		})
+		associations = append(associations, &azurenetworkv1.SubnetNetworkSecurityGroupAssociation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: azurenetworkv1.SubnetNetworkSecurityGroupAssociationSpec{
+				ForProvider: azurenetworkv1.SubnetNetworkSecurityGroupAssociationParameters{
+					NetworkSecurityGroupIDRef: &xpv1.Reference{Name: securityGroup.GetName()},
+					SubnetIDRef:               &xpv1.Reference{Name: name},
+				},
+				ResourceSpec: xpv1.ResourceSpec{
+					ProviderConfigReference: providerConfigRef,
+				},
+			},
+		})
+	}
+	var rules []*azurenetworkv1.SecurityRule
+	for i, rule := range data.FirewallRules {
+		priority := float64(azureFirstPriority + i*azurePriorityStep)
+		params := azurenetworkv1.SecurityRuleParameters{
+			Access:                      utils.StrPtr("Allow"),
+			Direction:                   utils.StrPtr("Inbound"),
+			Priority:                    &priority,
+			Protocol:                    utils.StrPtr(azureProtocol(rule.Protocol)),
+			SourcePortRange:             utils.StrPtr("*"),
+			DestinationAddressPrefix:    utils.StrPtr("*"),
+			ResourceGroupNameRef:        resourceGroupRef,
+			NetworkSecurityGroupNameRef: &xpv1.Reference{Name: securityGroup.GetName()},
+		}
+		if len(rule.Ports) == 0 {
+			params.DestinationPortRange = utils.StrPtr("*")
+		}
+		for _, ports := range rule.Ports {
+			params.DestinationPortRanges = append(params.DestinationPortRanges, utils.StrPtr(ports))
+		}
+		if len(rule.SourceRanges) == 0 {
+			params.SourceAddressPrefix = utils.StrPtr("*")
+		}
+		for _, source := range rule.SourceRanges {
+			params.SourceAddressPrefixes = append(params.SourceAddressPrefixes, utils.StrPtr(source))
+		}
+		rules = append(rules, &azurenetworkv1.SecurityRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: RuleResourceName(data.Name, rule.Name),
+			},
+			Spec: azurenetworkv1.SecurityRuleSpec{
+				ForProvider: params,
+				ResourceSpec: xpv1.ResourceSpec{
+					ProviderConfigReference: providerConfigRef,
+				},
+			},
+		})
+	}
+	return resourceGroup, virtualNetwork, subnets, securityGroup, rules, associations
+}
+
+// azureProtocol returns the protocol name security rules use.
+func azureProtocol(protocol string) string {
+	switch protocol {
+	case ProtocolTCP:
+		return "Tcp"
+	case ProtocolUDP:
+		return "Udp"
+	case ProtocolICMP:
+		return "Icmp"
+	}
+	return "*"
+}
+
+// sourceRanges returns the sources of a rule, any address when none are
+// given.
+func sourceRanges(rule FirewallRule) []string {
+	if len(rule.SourceRanges) == 0 {
+		return []string{anyAddress}
+	}
+	return rule.SourceRanges
+}
+
+// ObserveGCP fills the status with the ids of the observed network and
+// subnetworks. The network is ready once all of its resources are.
+func (n *Network) ObserveGCP(network *gcpcomputev1.Network, subnetworks []*gcpcomputev1.Subnetwork, firewalls []*gcpcomputev1.Firewall) {
+	n.Status.NetworkID = utils.StrValue(network.Status.AtProvider.ID)
+	ids := map[string]*string{}
+	managed := []client.Object{network}
+	for _, subnetwork := range subnetworks {
+		ids[subnetwork.GetName()] = subnetwork.Status.AtProvider.ID
+		managed = append(managed, subnetwork)
+	}
+	for _, firewall := range firewalls {
+		managed = append(managed, firewall)
+	}
+	n.observeSubnets(ids)
+	utils.MirrorLeastReady(&n.Status.ConditionedStatus, managed...)
+}
+
+// ObserveAWS fills the status with the ids of the observed VPC and subnets.
+// The network is ready once all of its resources are.
+func (n *Network) ObserveAWS(vpc *awscomputev1.VPC, subnets []*awscomputev1.Subnet, securityGroup *awscomputev1.SecurityGroup, rules []*awscomputev1.SecurityGroupRule) {
+	n.Status.NetworkID = utils.StrValue(vpc.Status.AtProvider.ID)
+	ids := map[string]*string{}
+	managed := []client.Object{vpc, securityGroup}
+	for _, subnet := range subnets {
+		ids[subnet.GetName()] = subnet.Status.AtProvider.ID
+		managed = append(managed, subnet)
+	}
+	for _, rule := range rules {
+		managed = append(managed, rule)
+	}
+	n.observeSubnets(ids)
+	utils.MirrorLeastReady(&n.Status.ConditionedStatus, managed...)
+}
+
+// ObserveAzure fills the status with the ids of the observed virtual network
+// and subnets. The network is ready once all of its resources are.
+func (n *Network) ObserveAzure(virtualNetwork *azurenetworkv1.VirtualNetwork, subnets []*azurenetworkv1.Subnet, securityGroup *azurenetworkv1.SecurityGroup, rules []*azurenetworkv1.SecurityRule, associations []*azurenetworkv1.SubnetNetworkSecurityGroupAssociation) {
+	n.Status.NetworkID = utils.StrValue(virtualNetwork.Status.AtProvider.ID)
+	ids := map[string]*string{}
+	managed := []client.Object{virtualNetwork, securityGroup}
+	for _, subnet := range subnets {
+		ids[subnet.GetName()] = subnet.Status.AtProvider.ID
+		managed = append(managed, subnet)
+	}
+	for _, rule := range rules {
+		managed = append(managed, rule)
+	}
+	for _, association := range associations {
+		managed = append(managed, association)
+	}
+	n.observeSubnets(ids)
+	utils.MirrorLeastReady(&n.Status.ConditionedStatus, managed...)
+}
+
+// observeSubnets records the provider ids of the subnets, given by the name
+// of their managed resources.
+func (n *Network) observeSubnets(ids map[string]*string) {
+	n.Status.SubnetIDs = nil
+	for _, subnet := range n.Spec.ForProvider.Subnets {
+		id := utils.StrValue(ids[SubnetResourceName(n.Spec.ForProvider.Name, subnet.Name)])
+		if id == "" {
+			continue
+		}
+		if n.Status.SubnetIDs == nil {
+			n.Status.SubnetIDs = map[string]string{}
+		}
+		n.Status.SubnetIDs[subnet.Name] = id
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -33,14 +34,35 @@ type StorageBucketMissionRef struct {
 type StorageBucketsSpec struct {
 	MissionRef  StorageBucketMissionRef `json:"missionRef,omitempty"`
 	ForProvider ProviderData            `json:"forProvider,omitempty"`
+	// DeletionPolicy decides whether the cloud resources are deleted or
+	// orphaned along with the bucket. Defaults to the Mission policy.
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
+// StorageBucketsStatus tells where the bucket can be reached, independently
+// of which provider it is stored in.
 type StorageBucketsStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	Provider               string `json:"provider,omitempty"`
+	// URL addresses the bucket, e.g. gs://name, s3://name or the blob
+	// container endpoint on Azure.
+	URL string `json:"url,omitempty"`
+	// ARN is only reported by AWS.
+	ARN    string `json:"arn,omitempty"`
+	Region string `json:"region,omitempty"`
+	// SelfLink is the provider API path of the bucket.
+	SelfLink string `json:"selfLink,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+//+kubebuilder:printcolumn:name="PROVIDER",type="string",JSONPath=".status.provider"
+//+kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 type StorageBuckets struct {
 	metav1.TypeMeta   `json:",inline"`
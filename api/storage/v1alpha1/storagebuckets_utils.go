@@ -0,0 +1,244 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	awsstoragev1 "github.com/upbound/provider-aws/apis/s3/v1beta1"
+	azurev1 "github.com/upbound/provider-azure/apis/azure/v1beta1"
+	azurestoragev1 "github.com/upbound/provider-azure/apis/storage/v1beta1"
+	gcpstoragev1 "github.com/upbound/provider-gcp/apis/storage/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	validation "k8s.io/apimachinery/pkg/util/validation"
+
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// AzureStorageAccountName derives a valid storage account name, 3 to 24
+// lowercase letters and digits, from a bucket name. Names with fewer than 3
+// letters or digits cannot be used.
+func AzureStorageAccountName(name string) (string, error) {
+	var b strings.Builder
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		}
+	}
+	account := b.String()
+	if len(account) < 3 {
+		return "", errors.New("Azure bucket names need at least 3 letters or digits to name the storage account.")
+	}
+	if len(account) > 24 {
+		account = account[:24]
+	}
+	return account, nil
+}
+
+// Bucket naming rules of each provider, without the length limits that are
+// checked separately.
+var (
+	gcpBucketNamePattern      = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*[a-z0-9]$`)
+	awsBucketNamePattern      = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*[a-z0-9]$`)
+	azureContainerNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+func (b *StorageBuckets) GCPVerify() error {
+	if err := b.GenericVerify(); err != nil {
+		return err
+	}
+	name := b.Spec.ForProvider.Name
+	if !gcpBucketNamePattern.MatchString(name) || strings.HasPrefix(name, "goog") {
+		return errors.New("GCP bucket names must use lowercase letters, digits, dots, hyphens or underscores and may not start with \"goog\".")
+	}
+	return nil
+}
+
+func (b *StorageBuckets) AWSVerify() error {
+	if err := b.GenericVerify(); err != nil {
+		return err
+	}
+	name := b.Spec.ForProvider.Name
+	if !awsBucketNamePattern.MatchString(name) || strings.Contains(name, "..") || strings.HasPrefix(name, "xn--") {
+		return errors.New("S3 bucket names must use lowercase letters, digits, dots or hyphens and start and end with a letter or digit.")
+	}
+	if net.ParseIP(name) != nil {
+		return errors.New("S3 bucket names may not be formatted as an IP address.")
+	}
+	return nil
+}
+
+func (b *StorageBuckets) AzureVerify() error {
+	if err := b.GenericVerify(); err != nil {
+		return err
+	}
+	if !azureContainerNamePattern.MatchString(b.Spec.ForProvider.Name) {
+		return errors.New("Azure container names must use lowercase letters, digits or single hyphens and start and end with a letter or digit.")
+	}
+	_, err := AzureStorageAccountName(b.Spec.ForProvider.Name)
+	return err
+}
+
+// GenericVerify checks the length every provider allows for bucket names and
+// that the name can be used for the managed resources created for it.
+func (b *StorageBuckets) GenericVerify() error {
+	name := b.Spec.ForProvider.Name
+	if len(name) < 3 || len(name) > 63 {
+		return errors.New("Bucket names must have between 3 and 63 characters.")
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return errors.New(strings.Join(errs, " "))
+	}
+	return nil
+}
+
+func (b *StorageBuckets) Convert2GCP(providerConfig string) *gcpstoragev1.Bucket {
+	return &gcpstoragev1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: b.Spec.ForProvider.Name,
+		},
+		Spec: gcpstoragev1.BucketSpec{
+			ForProvider: gcpstoragev1.BucketParameters{
+				Location: &b.Spec.ForProvider.Location,
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{
+					Name: providerConfig,
+				},
+			},
+		},
+	}
+}
+
+func (b *StorageBuckets) Convert2AWS(providerConfig string) *awsstoragev1.Bucket {
+	return &awsstoragev1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: b.Spec.ForProvider.Name,
+		},
+		Spec: awsstoragev1.BucketSpec{
+			ForProvider: awsstoragev1.BucketParameters{
+				Region: &b.Spec.ForProvider.Location,
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{
+					Name: providerConfig,
+				},
+			},
+		},
+	}
+}
+
+// Convert2Azure returns the resource group, storage account and blob
+// container backing a bucket on Azure.
+func (b *StorageBuckets) Convert2Azure(providerConfig string) (*azurev1.ResourceGroup, *azurestoragev1.Account, *azurestoragev1.Container, error) {
+	data := b.Spec.ForProvider
+	accountName, err := AzureStorageAccountName(data.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	providerConfigRef := &xpv1.Reference{
+		Name: providerConfig,
+	}
+	resourceGroup := &azurev1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: utils.AzureResourceGroupName("StorageBuckets", data.Name),
+		},
+		Spec: azurev1.ResourceGroupSpec{
+			ForProvider: azurev1.ResourceGroupParameters{
+				Location: &data.Location,
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	tier, replication := "Standard", "LRS"
+	account := &azurestoragev1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: accountName,
+		},
+		Spec: azurestoragev1.AccountSpec{
+			ForProvider: azurestoragev1.AccountParameters{
+				Location:               &data.Location,
+				AccountTier:            &tier,
+				AccountReplicationType: &replication,
+				ResourceGroupNameRef:   &xpv1.Reference{Name: resourceGroup.GetName()},
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	access := "private"
+	container := &azurestoragev1.Container{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: data.Name,
+		},
+		Spec: azurestoragev1.ContainerSpec{
+			ForProvider: azurestoragev1.ContainerParameters{
+				ContainerAccessType:   &access,
+				StorageAccountNameRef: &xpv1.Reference{Name: account.GetName()},
+			},
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: providerConfigRef,
+			},
+		},
+	}
+	return resourceGroup, account, container, nil
+}
+
+// ObserveGCP fills the status with the details of the observed Cloud Storage
+// bucket.
+func (b *StorageBuckets) ObserveGCP(bucket *gcpstoragev1.Bucket) {
+	observed := bucket.Status.AtProvider
+	b.Status.URL = utils.StrValue(observed.URL)
+	b.Status.ARN = ""
+	b.Status.Region = utils.StrValue(observed.Location)
+	b.Status.SelfLink = utils.StrValue(observed.SelfLink)
+	utils.MirrorConditions(&b.Status.ConditionedStatus, bucket)
+}
+
+// ObserveAWS fills the status with the details of the observed S3 bucket.
+func (b *StorageBuckets) ObserveAWS(bucket *awsstoragev1.Bucket) {
+	observed := bucket.Status.AtProvider
+	b.Status.URL = ""
+	if observed.ID != nil {
+		b.Status.URL = "s3://" + *observed.ID
+	}
+	b.Status.ARN = utils.StrValue(observed.Arn)
+	b.Status.Region = utils.StrValue(observed.Region)
+	b.Status.SelfLink = ""
+	if observed.BucketRegionalDomainName != nil {
+		b.Status.SelfLink = "https://" + *observed.BucketRegionalDomainName
+	}
+	utils.MirrorConditions(&b.Status.ConditionedStatus, bucket)
+}
+
+// ObserveAzure fills the status with the details of the observed storage
+// account and blob container. Conditions are taken from the container, which
+// is only ready once its account is.
+func (b *StorageBuckets) ObserveAzure(account *azurestoragev1.Account, container *azurestoragev1.Container) {
+	b.Status.URL = utils.StrValue(container.Status.AtProvider.ID)
+	b.Status.ARN = ""
+	b.Status.Region = utils.StrValue(account.Status.AtProvider.Location)
+	b.Status.SelfLink = utils.StrValue(container.Status.AtProvider.ResourceManagerID)
+	utils.MirrorConditions(&b.Status.ConditionedStatus, container)
+}
@@ -61,7 +61,7 @@ func (in *StorageBuckets) DeepCopyInto(out *StorageBuckets) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageBuckets.
@@ -134,6 +134,7 @@ func (in *StorageBucketsSpec) DeepCopy() *StorageBucketsSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageBucketsStatus) DeepCopyInto(out *StorageBucketsStatus) {
 	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageBucketsStatus.
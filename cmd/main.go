@@ -32,16 +32,41 @@ import (
 	controllerscheme "sigs.k8s.io/controller-runtime/pkg/scheme"
 
 	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	cpv1alpha1 "github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	awscomputev1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	awsrdsv1 "github.com/upbound/provider-aws/apis/rds/v1beta1"
+	awsstoragev1 "github.com/upbound/provider-aws/apis/s3/v1beta1"
 	awsv1 "github.com/upbound/provider-aws/apis/v1beta1"
+	azurev1 "github.com/upbound/provider-azure/apis/azure/v1beta1"
+	azurecomputev1 "github.com/upbound/provider-azure/apis/compute/v1beta1"
+	azuredbv1 "github.com/upbound/provider-azure/apis/dbforpostgresql/v1beta1"
+	azurenetworkv1 "github.com/upbound/provider-azure/apis/network/v1beta1"
+	azurestoragev1 "github.com/upbound/provider-azure/apis/storage/v1beta1"
 	azrv1 "github.com/upbound/provider-azure/apis/v1beta1"
+	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	gcpsqlv1 "github.com/upbound/provider-gcp/apis/sql/v1beta1"
+	gcpstoragev1 "github.com/upbound/provider-gcp/apis/storage/v1beta1"
 	gcpv1 "github.com/upbound/provider-gcp/apis/v1beta1"
 
 	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
 	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
 	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
 	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	computecontroller "github.com/holy-tech/Mission-Control-Operator/internal/controller/compute"
+	databasecontroller "github.com/holy-tech/Mission-Control-Operator/internal/controller/database"
+	encryption "github.com/holy-tech/Mission-Control-Operator/internal/controller/encryption"
 	missioncontroler "github.com/holy-tech/Mission-Control-Operator/internal/controller/mission"
 	missionkeycontroler "github.com/holy-tech/Mission-Control-Operator/internal/controller/missionkey"
+	networkcontroller "github.com/holy-tech/Mission-Control-Operator/internal/controller/network"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	storagecontroller "github.com/holy-tech/Mission-Control-Operator/internal/controller/storage"
+	computewebhook "github.com/holy-tech/Mission-Control-Operator/internal/webhook/compute/v1alpha1"
+	databasewebhook "github.com/holy-tech/Mission-Control-Operator/internal/webhook/database/v1alpha1"
+	missionwebhook "github.com/holy-tech/Mission-Control-Operator/internal/webhook/mission/v1alpha1"
+	networkwebhook "github.com/holy-tech/Mission-Control-Operator/internal/webhook/network/v1alpha1"
+	storagewebhook "github.com/holy-tech/Mission-Control-Operator/internal/webhook/storage/v1alpha1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -68,11 +93,32 @@ func init() {
 	utilruntime.Must(missionv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(computev1alpha1.AddToScheme(scheme))
 	utilruntime.Must(storagev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(networkv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(databasev1alpha1.AddToScheme(scheme))
 
 	buildScheme(scheme, "pkg.crossplane.io", "v1", &cpv1.Provider{}, &cpv1.ProviderList{})
+	buildScheme(scheme, "pkg.crossplane.io", "v1alpha1", &cpv1alpha1.ControllerConfig{}, &cpv1alpha1.ControllerConfigList{})
 	buildScheme(scheme, "gcp.upbound.io", "v1beta1", &gcpv1.ProviderConfig{}, &gcpv1.ProviderConfigList{})
 	buildScheme(scheme, "aws.upbound.io", "v1beta1", &awsv1.ProviderConfig{}, &awsv1.ProviderConfigList{})
 	buildScheme(scheme, "azure.upbound.io", "v1beta1", &azrv1.ProviderConfig{}, &azrv1.ProviderConfigList{})
+	buildScheme(scheme, "compute.gcp.upbound.io", "v1beta1", &gcpcomputev1.Instance{}, &gcpcomputev1.InstanceList{},
+		&gcpcomputev1.Network{}, &gcpcomputev1.NetworkList{}, &gcpcomputev1.Subnetwork{}, &gcpcomputev1.SubnetworkList{},
+		&gcpcomputev1.Firewall{}, &gcpcomputev1.FirewallList{})
+	buildScheme(scheme, "storage.gcp.upbound.io", "v1beta1", &gcpstoragev1.Bucket{}, &gcpstoragev1.BucketList{})
+	buildScheme(scheme, "sql.gcp.upbound.io", "v1beta1", &gcpsqlv1.DatabaseInstance{}, &gcpsqlv1.DatabaseInstanceList{}, &gcpsqlv1.User{}, &gcpsqlv1.UserList{})
+	buildScheme(scheme, "ec2.aws.upbound.io", "v1beta1", &awscomputev1.Instance{}, &awscomputev1.InstanceList{},
+		&awscomputev1.VPC{}, &awscomputev1.VPCList{}, &awscomputev1.Subnet{}, &awscomputev1.SubnetList{},
+		&awscomputev1.SecurityGroup{}, &awscomputev1.SecurityGroupList{}, &awscomputev1.SecurityGroupRule{}, &awscomputev1.SecurityGroupRuleList{})
+	buildScheme(scheme, "s3.aws.upbound.io", "v1beta1", &awsstoragev1.Bucket{}, &awsstoragev1.BucketList{})
+	buildScheme(scheme, "rds.aws.upbound.io", "v1beta1", &awsrdsv1.Instance{}, &awsrdsv1.InstanceList{})
+	buildScheme(scheme, "azure.upbound.io", "v1beta1", &azurev1.ResourceGroup{}, &azurev1.ResourceGroupList{})
+	buildScheme(scheme, "network.azure.upbound.io", "v1beta1", &azurenetworkv1.NetworkInterface{}, &azurenetworkv1.NetworkInterfaceList{},
+		&azurenetworkv1.VirtualNetwork{}, &azurenetworkv1.VirtualNetworkList{}, &azurenetworkv1.Subnet{}, &azurenetworkv1.SubnetList{},
+		&azurenetworkv1.SecurityGroup{}, &azurenetworkv1.SecurityGroupList{}, &azurenetworkv1.SecurityRule{}, &azurenetworkv1.SecurityRuleList{},
+		&azurenetworkv1.SubnetNetworkSecurityGroupAssociation{}, &azurenetworkv1.SubnetNetworkSecurityGroupAssociationList{})
+	buildScheme(scheme, "compute.azure.upbound.io", "v1beta1", &azurecomputev1.LinuxVirtualMachine{}, &azurecomputev1.LinuxVirtualMachineList{})
+	buildScheme(scheme, "storage.azure.upbound.io", "v1beta1", &azurestoragev1.Account{}, &azurestoragev1.AccountList{}, &azurestoragev1.Container{}, &azurestoragev1.ContainerList{})
+	buildScheme(scheme, "dbforpostgresql.azure.upbound.io", "v1beta1", &azuredbv1.FlexibleServer{}, &azuredbv1.FlexibleServerList{})
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -80,8 +126,24 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var encryptionKeyFile string
+	var installProviders bool
+	var providerRegistry string
+	var providerVersions string
+	var enableCompute bool
+	var enableStorage bool
+	var enableNetwork bool
+	var enableDatabase bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&encryptionKeyFile, "encryption-key-file", "", "The RSA private key decrypting MissionKeys encrypted for the \"local\" provider.")
+	flag.BoolVar(&enableCompute, "enable-compute", true, "Reconcile VirtualMachines.")
+	flag.BoolVar(&enableStorage, "enable-storage", true, "Reconcile StorageBuckets.")
+	flag.BoolVar(&enableNetwork, "enable-network", true, "Reconcile Networks.")
+	flag.BoolVar(&enableDatabase, "enable-database", true, "Reconcile Databases.")
+	flag.BoolVar(&installProviders, "install-providers", false, "Install the Crossplane provider packages used by Missions and their resources.")
+	flag.StringVar(&providerRegistry, "provider-registry", providers.DefaultRegistry, "The registry provider packages are installed from.")
+	flag.StringVar(&providerVersions, "provider-versions", "", "Provider package versions overriding the defaults, e.g. \"gcp=v0.37.0,aws=v0.41.0\".")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -93,6 +155,25 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if encryptionKeyFile != "" {
+		local, err := encryption.NewLocal(encryptionKeyFile)
+		if err != nil {
+			setupLog.Error(err, "unable to read encryption key")
+			os.Exit(1)
+		}
+		encryption.Register(local)
+	}
+
+	var packages *providers.Packages
+	if installProviders {
+		versions, err := providers.ParseVersions(providerVersions)
+		if err != nil {
+			setupLog.Error(err, "unable to read provider versions")
+			os.Exit(1)
+		}
+		packages = &providers.Packages{Registry: providerRegistry, Versions: versions}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: probeAddr,
@@ -104,9 +185,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+	if err := clients.SetupIndexes(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to set up field indexes")
+		os.Exit(1)
+	}
+
 	if err = (&missioncontroler.MissionReconciler{
 		MissionClient: clients.MissionClient{
-			Client: mgr.GetClient(),
+			Client:   mgr.GetClient(),
+			Packages: packages,
 		},
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("Mission"),
@@ -116,31 +204,95 @@ func main() {
 	}
 	if err = (&missionkeycontroler.MissionKeyReconciler{
 		MissionClient: clients.MissionClient{
-			Client: mgr.GetClient(),
+			Client:   mgr.GetClient(),
+			Packages: packages,
 		},
-		Scheme: mgr.GetScheme(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("MissionKey"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MissionKey")
 		os.Exit(1)
 	}
-	// if err = (&computecontroller.VirtualMachineReconciler{
-	// 	MissionClient: clients.MissionClient{
-	// 		Client: mgr.GetClient(),
-	// 	},
-	// 	Scheme: mgr.GetScheme(),
-	// }).SetupWithManager(mgr); err != nil {
-	// 	setupLog.Error(err, "unable to create controller", "controller", "VirtualMachine")
-	// 	os.Exit(1)
-	// }
-	// if err = (&storagecontroller.StorageBucketsReconciler{
-	// 	MissionClient: clients.MissionClient{
-	// 		Client: mgr.GetClient(),
-	// 	},
-	// 	Scheme: mgr.GetScheme(),
-	// }).SetupWithManager(mgr); err != nil {
-	// 	setupLog.Error(err, "unable to create controller", "controller", "StorageBuckets")
-	// 	os.Exit(1)
-	// }
+	if enableCompute && controllerRuns(mgr, "VirtualMachine", providers.KindVirtualMachine, packages) {
+		if err = (&computecontroller.VirtualMachineReconciler{
+			MissionClient: clients.MissionClient{
+				Client:   mgr.GetClient(),
+				Packages: packages,
+			},
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("VirtualMachine"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "VirtualMachine")
+			os.Exit(1)
+		}
+	}
+	if enableStorage && controllerRuns(mgr, "StorageBuckets", providers.KindStorageBuckets, packages) {
+		if err = (&storagecontroller.StorageBucketsReconciler{
+			MissionClient: clients.MissionClient{
+				Client:   mgr.GetClient(),
+				Packages: packages,
+			},
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("StorageBuckets"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "StorageBuckets")
+			os.Exit(1)
+		}
+	}
+	if enableNetwork && controllerRuns(mgr, "Network", providers.KindNetwork, packages) {
+		if err = (&networkcontroller.NetworkReconciler{
+			MissionClient: clients.MissionClient{
+				Client:   mgr.GetClient(),
+				Packages: packages,
+			},
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("Network"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Network")
+			os.Exit(1)
+		}
+	}
+	if enableDatabase && controllerRuns(mgr, "Database", providers.KindDatabase, packages) {
+		if err = (&databasecontroller.DatabaseReconciler{
+			MissionClient: clients.MissionClient{
+				Client:   mgr.GetClient(),
+				Packages: packages,
+			},
+			Scheme:    mgr.GetScheme(),
+			Recorder:  mgr.GetEventRecorderFor("Database"),
+			APIReader: mgr.GetAPIReader(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Database")
+			os.Exit(1)
+		}
+	}
+	// Webhooks need serving certificates, set ENABLE_WEBHOOKS=false to run locally without them.
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = missionwebhook.SetupMissionWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Mission")
+			os.Exit(1)
+		}
+		if err = missionwebhook.SetupMissionKeyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MissionKey")
+			os.Exit(1)
+		}
+		if err = computewebhook.SetupVirtualMachineWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "VirtualMachine")
+			os.Exit(1)
+		}
+		if err = storagewebhook.SetupStorageBucketsWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "StorageBuckets")
+			os.Exit(1)
+		}
+		if err = networkwebhook.SetupNetworkWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Network")
+			os.Exit(1)
+		}
+		if err = databasewebhook.SetupDatabaseWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Database")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -153,8 +305,24 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// controllerRuns reports whether the controller of a kind can run. It is
+// skipped when none of the CRDs of its managed resources are installed,
+// unless the operator installs the provider packages serving them.
+func controllerRuns(mgr ctrl.Manager, controller, kind string, packages *providers.Packages) bool {
+	installed, _, err := clients.InstalledTypes(mgr.GetScheme(), mgr.GetRESTMapper(), providers.ManagedTypes(kind))
+	if err != nil {
+		setupLog.Error(err, "unable to look up CRDs", "controller", controller)
+		os.Exit(1)
+	}
+	if len(installed) == 0 && packages == nil {
+		setupLog.Info("skipping controller, no Crossplane CRDs of its managed resources are installed", "controller", controller)
+		return false
+	}
+	return true
+}
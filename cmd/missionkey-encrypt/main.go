@@ -0,0 +1,93 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// missionkey-encrypt prints the encrypted data of a MissionKey for the
+// "local" encryption provider of the operator. The data only opens in the
+// MissionKey of the given namespace, name and type.
+//
+//	missionkey-encrypt --public-key operator.pub --in key.json --namespace default --name gcp-key --type gcp
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"os"
+
+	encryption "github.com/holy-tech/Mission-Control-Operator/internal/controller/encryption"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+func main() {
+	var publicKeyFile, in, namespace, name, keyType string
+	flag.StringVar(&publicKeyFile, "public-key", "", "PEM encoded RSA public key of the operator.")
+	flag.StringVar(&in, "in", "", "File with the credentials to encrypt.")
+	flag.StringVar(&namespace, "namespace", "default", "Namespace of the MissionKey.")
+	flag.StringVar(&name, "name", "", "Name of the MissionKey.")
+	flag.StringVar(&keyType, "type", "", "Provider type of the MissionKey, e.g. gcp.")
+	flag.Parse()
+	if publicKeyFile == "" || in == "" || name == "" || keyType == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	publicKey, err := readPublicKey(publicKeyFile)
+	if err != nil {
+		fail(err)
+	}
+	data, err := os.ReadFile(in)
+	if err != nil {
+		fail(err)
+	}
+	encryptedKey, sealed, err := encryption.Seal(publicKey, data, encryption.AdditionalData(namespace, name, utils.NormalizeProvider(keyType)))
+	if err != nil {
+		fail(err)
+	}
+	fmt.Printf("data: %s\n", base64.StdEncoding.EncodeToString(sealed))
+	fmt.Printf("encryption:\n  provider: %s\n  encryptedKey: %s\n", encryption.LocalName, base64.StdEncoding.EncodeToString(encryptedKey))
+}
+
+// readPublicKey reads a PKIX or PKCS#1 RSA public key, as written by
+// "openssl rsa -pubout".
+func readPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an RSA public key", path)
+	}
+	return rsaKey, nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"sync"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	apiutil "sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	reconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	v1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+)
+
+// Fields indexed by the manager cache, used to find the objects referencing
+// a Mission or MissionKey.
+const (
+	// MissionRefField indexes VirtualMachines, StorageBuckets, Networks and
+	// Databases by the name of their Mission.
+	MissionRefField = "spec.missionRef.missionName"
+	// CredentialsField indexes Missions by the "namespace/name" of the
+	// MissionKeys of their packages.
+	CredentialsField = "spec.packages.credentials"
+	// SecretRefField indexes MissionKeys with a Secret source by the
+	// "namespace/name" of the Secret.
+	SecretRefField = "spec.secretRef"
+	// ControllerField indexes managed resources by the UID of the resource
+	// controlling them.
+	ControllerField = "metadata.controller"
+)
+
+// ManagedKinds are the kinds whose managed resources are indexed by their
+// controller.
+var ManagedKinds = []string{providers.KindVirtualMachine, providers.KindStorageBuckets, providers.KindNetwork, providers.KindDatabase}
+
+// SetupIndexes registers the field indexes shared by all controllers. It
+// has to be called once, before the controllers are set up.
+func SetupIndexes(ctx context.Context, mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(ctx, &v1alpha1.Mission{}, CredentialsField, IndexCredentials); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &v1alpha1.MissionKey{}, SecretRefField, IndexSecretRef); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &computev1alpha1.VirtualMachine{}, MissionRefField, IndexMissionRef); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &storagev1alpha1.StorageBuckets{}, MissionRefField, IndexMissionRef); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &networkv1alpha1.Network{}, MissionRefField, IndexMissionRef); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &databasev1alpha1.Database{}, MissionRefField, IndexMissionRef); err != nil {
+		return err
+	}
+	var managed []client.Object
+	for _, kind := range ManagedKinds {
+		managed = append(managed, providers.ManagedTypes(kind)...)
+	}
+	installed, _, err := InstalledTypes(mgr.GetScheme(), mgr.GetRESTMapper(), managed)
+	if err != nil {
+		return err
+	}
+	return IndexControlled(ctx, indexer, mgr.GetScheme(), installed)
+}
+
+// controllerIndexes records the types indexed by ControllerField in each
+// cache, as types shared by several kinds can only be indexed once.
+var controllerIndexes = struct {
+	sync.Mutex
+	indexed map[client.FieldIndexer]map[schema.GroupVersionKind]bool
+}{indexed: map[client.FieldIndexer]map[schema.GroupVersionKind]bool{}}
+
+// IndexControlled indexes objects of the given types by ControllerField,
+// unless their type already is.
+func IndexControlled(ctx context.Context, indexer client.FieldIndexer, scheme *runtime.Scheme, objects []client.Object) error {
+	controllerIndexes.Lock()
+	defer controllerIndexes.Unlock()
+	indexed, ok := controllerIndexes.indexed[indexer]
+	if !ok {
+		indexed = map[schema.GroupVersionKind]bool{}
+		controllerIndexes.indexed[indexer] = indexed
+	}
+	for _, object := range objects {
+		gvk, err := apiutil.GVKForObject(object, scheme)
+		if err != nil {
+			return err
+		}
+		if indexed[gvk] {
+			continue
+		}
+		if err := indexer.IndexField(ctx, object, ControllerField, IndexController); err != nil {
+			return err
+		}
+		indexed[gvk] = true
+	}
+	return nil
+}
+
+// IndexController returns the UID of the controller of an object.
+func IndexController(object client.Object) []string {
+	if owner := metav1.GetControllerOf(object); owner != nil {
+		return []string{string(owner.UID)}
+	}
+	return nil
+}
+
+// IndexCredentials returns the MissionKeys of the packages of a Mission.
+func IndexCredentials(object client.Object) []string {
+	var keys []string
+	for _, pkg := range object.(*v1alpha1.Mission).Spec.Packages {
+		keys = append(keys, pkg.KeyRef().String())
+	}
+	return keys
+}
+
+// IndexSecretRef returns the Secret a MissionKey with a Secret source reads
+// its credentials from.
+func IndexSecretRef(object client.Object) []string {
+	key := object.(*v1alpha1.MissionKey)
+	if key.Spec.Source != xpv1.CredentialsSourceSecret || key.Spec.SecretRef == nil {
+		return nil
+	}
+	ref := key.Spec.SecretRef
+	return []string{types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}.String()}
+}
+
+// IndexMissionRef returns the Mission of a VirtualMachine, StorageBuckets,
+// Network or Database.
+func IndexMissionRef(object client.Object) []string {
+	switch o := object.(type) {
+	case *computev1alpha1.VirtualMachine:
+		return []string{o.Spec.MissionRef.MissionName}
+	case *storagev1alpha1.StorageBuckets:
+		return []string{o.Spec.MissionRef.MissionName}
+	case *networkv1alpha1.Network:
+		return []string{o.Spec.MissionRef.MissionName}
+	case *databasev1alpha1.Database:
+		return []string{o.Spec.MissionRef.MissionName}
+	}
+	return nil
+}
+
+// MissionsForKey lists the Missions with a package using the MissionKey.
+func (r *MissionClient) MissionsForKey(ctx context.Context, key client.Object) ([]v1alpha1.Mission, error) {
+	missions := &v1alpha1.MissionList{}
+	err := r.List(ctx, missions, client.MatchingFields{CredentialsField: client.ObjectKeyFromObject(key).String()})
+	return missions.Items, err
+}
+
+// RequestsForKey maps a MissionKey to the Missions using it.
+func (r *MissionClient) RequestsForKey(ctx context.Context, key client.Object) []reconcile.Request {
+	missions, err := r.MissionsForKey(ctx, key)
+	if err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(missions))
+	for _, mission := range missions {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: mission.GetName()}})
+	}
+	return requests
+}
+
+// RequestsForSecret maps a Secret to the MissionKeys reading their
+// credentials from it.
+func (r *MissionClient) RequestsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	keys := &v1alpha1.MissionKeyList{}
+	if err := r.List(ctx, keys, client.MatchingFields{SecretRefField: client.ObjectKeyFromObject(secret).String()}); err != nil {
+		return nil
+	}
+	return requestsForList(keys)
+}
+
+// RequestsForMissionRef returns a map function enqueueing the objects of
+// list that reference a Mission, for watches on Missions.
+func (r *MissionClient) RequestsForMissionRef(list client.ObjectList) func(ctx context.Context, mission client.Object) []reconcile.Request {
+	return func(ctx context.Context, mission client.Object) []reconcile.Request {
+		return r.requestsForMissions(ctx, list, mission.GetName())
+	}
+}
+
+// RequestsForMissionRefByKey returns a map function enqueueing the objects
+// of list that reference a Mission using a MissionKey, for watches on
+// MissionKeys.
+func (r *MissionClient) RequestsForMissionRefByKey(list client.ObjectList) func(ctx context.Context, key client.Object) []reconcile.Request {
+	return func(ctx context.Context, key client.Object) []reconcile.Request {
+		missions, err := r.MissionsForKey(ctx, key)
+		if err != nil {
+			return nil
+		}
+		names := make([]string, 0, len(missions))
+		for _, mission := range missions {
+			names = append(names, mission.GetName())
+		}
+		return r.requestsForMissions(ctx, list, names...)
+	}
+}
+
+// RequestsForPackage returns a map function enqueueing every object of list
+// when one of the provider packages installed by the operator changes, for
+// watches on Crossplane Providers.
+func (r *MissionClient) RequestsForPackage(list client.ObjectList) func(ctx context.Context, pkg client.Object) []reconcile.Request {
+	return func(ctx context.Context, pkg client.Object) []reconcile.Request {
+		if _, ok := providers.ForPackage(pkg.GetName()); !ok {
+			return nil
+		}
+		objects := list.DeepCopyObject().(client.ObjectList)
+		if err := r.List(ctx, objects); err != nil {
+			return nil
+		}
+		return requestsForList(objects)
+	}
+}
+
+func (r *MissionClient) requestsForMissions(ctx context.Context, list client.ObjectList, missionNames ...string) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, name := range missionNames {
+		objects := list.DeepCopyObject().(client.ObjectList)
+		if err := r.List(ctx, objects, client.MatchingFields{MissionRefField: name}); err != nil {
+			continue
+		}
+		requests = append(requests, requestsForList(objects)...)
+	}
+	return requests
+}
+
+func requestsForList(list client.ObjectList) []reconcile.Request {
+	var requests []reconcile.Request
+	_ = meta.EachListItem(list, func(object runtime.Object) error {
+		if o, ok := object.(client.Object); ok {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+		}
+		return nil
+	})
+	return requests
+}
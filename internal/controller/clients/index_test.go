@@ -0,0 +1,129 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	types "k8s.io/apimachinery/pkg/types"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	reconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	v1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+)
+
+func newIndexedClient(t *testing.T, objects ...client.Object) *MissionClient {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{v1alpha1.AddToScheme, computev1alpha1.AddToScheme, storagev1alpha1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithIndex(&v1alpha1.Mission{}, CredentialsField, IndexCredentials).
+		WithIndex(&v1alpha1.MissionKey{}, SecretRefField, IndexSecretRef).
+		WithIndex(&computev1alpha1.VirtualMachine{}, MissionRefField, IndexMissionRef).
+		WithIndex(&storagev1alpha1.StorageBuckets{}, MissionRefField, IndexMissionRef).
+		Build()
+	return &MissionClient{Client: c}
+}
+
+func TestRequestsForKey(t *testing.T) {
+	mission := func(name, namespace string) *v1alpha1.Mission {
+		return &v1alpha1.Mission{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.MissionSpec{Packages: []v1alpha1.PackageConfig{{
+				Provider:    "aws",
+				Credentials: v1alpha1.CredentialConfig{Name: "aws-key", Namespace: namespace},
+			}}},
+		}
+	}
+	vm := &computev1alpha1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "vm"},
+		Spec:       computev1alpha1.VirtualMachineSpec{MissionRef: computev1alpha1.VirtualMachineMissionRef{MissionName: "defaulted"}},
+	}
+	bucket := &storagev1alpha1.StorageBuckets{
+		ObjectMeta: metav1.ObjectMeta{Name: "bucket"},
+		Spec:       storagev1alpha1.StorageBucketsSpec{MissionRef: storagev1alpha1.StorageBucketMissionRef{MissionName: "team"}},
+	}
+	r := newIndexedClient(t, mission("defaulted", ""), mission("team", "team-a"), vm, bucket)
+	ctx := context.Background()
+	key := &v1alpha1.MissionKey{ObjectMeta: metav1.ObjectMeta{Name: "aws-key", Namespace: "default"}}
+
+	requests := r.RequestsForKey(ctx, key)
+	if !reflect.DeepEqual(requests, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "defaulted"}}}) {
+		t.Errorf("expected only the Mission using the default namespace, got %v", requests)
+	}
+	requests = r.RequestsForMissionRefByKey(&computev1alpha1.VirtualMachineList{})(ctx, key)
+	if !reflect.DeepEqual(requests, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "vm"}}}) {
+		t.Errorf("expected the VirtualMachine of the Mission, got %v", requests)
+	}
+	requests = r.RequestsForMissionRef(&storagev1alpha1.StorageBucketsList{})(ctx, mission("team", "team-a"))
+	if !reflect.DeepEqual(requests, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "bucket"}}}) {
+		t.Errorf("expected the StorageBuckets of the Mission, got %v", requests)
+	}
+	requests = r.RequestsForMissionRef(&computev1alpha1.VirtualMachineList{})(ctx, mission("team", "team-a"))
+	if len(requests) != 0 {
+		t.Errorf("expected no VirtualMachines, got %v", requests)
+	}
+}
+
+func TestRequestsForSecret(t *testing.T) {
+	key := func(name string, source xpv1.CredentialsSource) *v1alpha1.MissionKey {
+		return &v1alpha1.MissionKey{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "keys"},
+			Spec: v1alpha1.MissionKeySpec{
+				Source: source,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+					Key:             "creds",
+					SecretReference: xpv1.SecretReference{Name: "gcp", Namespace: "keys"},
+				}},
+			},
+		}
+	}
+	r := newIndexedClient(t, key("source", xpv1.CredentialsSourceSecret), key("inline", ""))
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "gcp", Namespace: "keys"}}
+	requests := r.RequestsForSecret(context.Background(), secret)
+	if !reflect.DeepEqual(requests, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "source", Namespace: "keys"}}}) {
+		t.Errorf("expected only the key with a Secret source, got %v", requests)
+	}
+}
+
+func TestRequestsForPackage(t *testing.T) {
+	mission := &v1alpha1.Mission{ObjectMeta: metav1.ObjectMeta{Name: "mission"}}
+	r := newIndexedClient(t, mission)
+	ctx := context.Background()
+	requests := r.RequestsForPackage(&v1alpha1.MissionList{})
+	family := &cpv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "provider-family-gcp"}}
+	if got := requests(ctx, family); !reflect.DeepEqual(got, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "mission"}}}) {
+		t.Errorf("expected the Mission, got %v", got)
+	}
+	other := &cpv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "provider-kubernetes"}}
+	if got := requests(ctx, other); len(got) != 0 {
+		t.Errorf("expected no requests for packages of other providers, got %v", got)
+	}
+}
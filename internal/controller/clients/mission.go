@@ -20,20 +20,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
+	"strings"
 
+	cpv1alpha1 "github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	types "k8s.io/apimachinery/pkg/types"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
+	apiutil "sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	v1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
 	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
 )
 
+// FieldManager is the field manager the operator applies objects with.
+const FieldManager = "mission-control-operator"
+
 type MissionClient struct {
 	client.Client
+	// Packages is set when the operator installs the Crossplane provider
+	// packages its resources need.
+	Packages *providers.Packages
+	// Managed watches the managed resources of the controller, and is told
+	// about the types installed by healthy packages.
+	Managed *ManagedWatches
 }
 
 type MissionObject interface {
@@ -46,46 +61,232 @@ func (r *MissionClient) GetMission(ctx context.Context, missionName string) (*v1
 	return &mission, err
 }
 
+// GetMissionKey returns the named MissionKey of a Mission package, from the
+// namespace the package names. Keys that do not allow the Mission are
+// refused.
 func (r *MissionClient) GetMissionKey(ctx context.Context, mission *v1alpha1.Mission, keyName string) (*v1alpha1.MissionKey, error) {
 	for _, pkg := range mission.Spec.Packages {
 		missionkey := v1alpha1.MissionKey{}
 		if pkg.Credentials.Name != keyName {
 			continue
 		}
-		err := r.Get(ctx, types.NamespacedName{Name: pkg.Credentials.Name}, &missionkey)
-		return &missionkey, err
+		if err := r.Get(ctx, pkg.KeyRef(), &missionkey); err != nil {
+			return &missionkey, err
+		}
+		if !missionkey.AllowsMission(mission.GetName()) {
+			return &missionkey, fmt.Errorf("MissionKey %s does not allow Mission %s", pkg.KeyRef(), mission.GetName())
+		}
+		return &missionkey, nil
 	}
 	msg := fmt.Sprintf("No credentials %s", keyName)
 	return &v1alpha1.MissionKey{}, errors.New(msg)
 }
 
-func (m *MissionClient) ReconcileObject(ctx context.Context, owner metav1.Object, object, expectedObject client.Object, specPaths ...string) error {
-	specPath := "Spec"
-	if len(specPaths) != 0 {
-		specPath = specPaths[0]
+// ConfirmProviderConfig ensures that the ProviderConfig a resource is going to
+// reference has already been created by its Mission.
+func (m *MissionClient) ConfirmProviderConfig(ctx context.Context, providerConfig client.Object) error {
+	err := m.Get(ctx, types.NamespacedName{Name: providerConfig.GetName()}, utils.NewObjectOf(providerConfig))
+	if k8serrors.IsNotFound(err) {
+		return fmt.Errorf("ProviderConfig %s does not exist yet", providerConfig.GetName())
+	}
+	return err
+}
+
+// ApplyObject server-side applies object with the operator field manager,
+// controlled by owner. Only the fields set on object are owned by the
+// operator, so fields defaulted by the API server or late-initialized by
+// Crossplane are left to them. Conflicting fields are taken over. On success
+// object holds the state returned by the API server.
+func (m *MissionClient) ApplyObject(ctx context.Context, owner metav1.Object, object client.Object) error {
+	if err := controllerutil.SetControllerReference(owner, object, m.Scheme()); err != nil {
+		return err
 	}
-	pcSpec := utils.GetValueOf(object, specPath)
-	epcSpec := utils.GetValueOf(expectedObject, specPath)
-	if pcSpec.Equal(reflect.Value{}) || epcSpec.Equal(reflect.Value{}) {
-		return errors.New("Could not reconcile object type")
+	return m.apply(ctx, object, client.ForceOwnership)
+}
+
+// InstallPackages applies a Crossplane Provider for each named package of p
+// and returns an error until all of them are installed and healthy. Nothing
+// is done unless the operator installs provider packages. Packages are
+// shared by every Mission and resource of a provider, so they have no owner
+// and are never deleted by the operator. Packages whose version is managed
+// by someone else are only checked. Packages run with the ControllerConfig
+// of InstallIdentity once there is one. Once all of them are healthy the
+// managed resource types they installed are watched.
+func (m *MissionClient) InstallPackages(ctx context.Context, p providers.Provider, names ...string) error {
+	if m.Packages == nil {
+		return nil
 	}
-	if err := controllerutil.SetControllerReference(owner, expectedObject, m.Scheme()); err != nil {
+	controllerConfig := providers.ControllerConfigName(p)
+	err := m.Get(ctx, types.NamespacedName{Name: controllerConfig}, &cpv1alpha1.ControllerConfig{})
+	if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		controllerConfig = ""
+	} else if err != nil {
 		return err
 	}
-	nsName := types.NamespacedName{
-		Name:      expectedObject.GetName(),
-		Namespace: expectedObject.GetNamespace(),
+	var errs []error
+	for _, name := range names {
+		pkg := m.Packages.Provider(p, name, controllerConfig)
+		err := m.apply(ctx, pkg)
+		if k8serrors.IsConflict(err) {
+			err = m.Get(ctx, types.NamespacedName{Name: name}, pkg)
+		}
+		if err != nil {
+			return err
+		}
+		errs = append(errs, providers.PackageHealthy(pkg))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
 	}
-	if err := m.Get(ctx, nsName, object); err != nil {
-		if k8serrors.IsNotFound(err) {
-			return m.Create(ctx, expectedObject)
+	if m.Managed == nil {
+		return nil
+	}
+	return m.Managed.WatchInstalled(ctx, p)
+}
+
+// InstallIdentity applies the ControllerConfig running the packages of p
+// with the workload identity of key, when the operator installs provider
+// packages. Packages are shared by every key of a provider, so only one key
+// can give them its identity. Another key takes the ControllerConfig over
+// once that key is gone or no longer has an identity. Like the packages it
+// has no owner and is never deleted by the operator.
+func (m *MissionClient) InstallIdentity(ctx context.Context, p providers.Provider, key *v1alpha1.MissionKey) error {
+	if m.Packages == nil || !key.UsesIdentity() {
+		return nil
+	}
+	config := key.Convert2ControllerConfig(providers.ControllerConfigName(p))
+	current := &cpv1alpha1.ControllerConfig{}
+	err := m.Get(ctx, types.NamespacedName{Name: config.GetName()}, current)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	holder := current.GetAnnotations()[v1alpha1.IdentityKeyAnnotation]
+	if err == nil && holder != "" && holder != config.GetAnnotations()[v1alpha1.IdentityKeyAnnotation] {
+		namespace, name, _ := strings.Cut(holder, "/")
+		other := &v1alpha1.MissionKey{}
+		err := m.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, other)
+		if err == nil && other.UsesIdentity() {
+			return fmt.Errorf("Provider packages of %s already run with the identity of MissionKey %s.", p.Name(), holder)
 		}
-	} else if !reflect.DeepEqual(pcSpec, epcSpec) {
-		if err := utils.SetValueOf(object, expectedObject, specPath); err != nil {
+		if client.IgnoreNotFound(err) != nil {
 			return err
 		}
-		err := m.Update(ctx, object)
+	}
+	return m.apply(ctx, config, client.ForceOwnership)
+}
+
+func (m *MissionClient) apply(ctx context.Context, object client.Object, opts ...client.PatchOption) error {
+	gvk, err := apiutil.GVKForObject(object, m.Scheme())
+	if err != nil {
 		return err
 	}
+	object.GetObjectKind().SetGroupVersionKind(gvk)
+	object.SetManagedFields(nil)
+	object.SetResourceVersion("")
+	return m.Patch(ctx, object, client.Apply, append(opts, client.FieldOwner(FieldManager))...)
+}
+
+// DeleteControlled deletes every object of the given types controlled by
+// owner and returns how many of them still exist. Objects are listed from
+// the cache through the ControllerField index. Types whose CRD is not
+// installed are skipped.
+func (m *MissionClient) DeleteControlled(ctx context.Context, owner metav1.Object, objectTypes []client.Object) (int, error) {
+	remaining := 0
+	err := m.eachControlled(ctx, owner, objectTypes, func(object client.Object) error {
+		remaining++
+		if object.GetDeletionTimestamp() != nil {
+			return nil
+		}
+		return client.IgnoreNotFound(m.Delete(ctx, object))
+	})
+	return remaining, err
+}
+
+// DeleteUndesired deletes the objects of the given types controlled by owner
+// that are not among desired, such as the managed resources of a rule that
+// was removed from or renamed in the spec of owner.
+func (m *MissionClient) DeleteUndesired(ctx context.Context, owner metav1.Object, objectTypes, desired []client.Object) error {
+	type objectKey struct {
+		kind schema.GroupKind
+		name types.NamespacedName
+	}
+	keep := make(map[objectKey]bool, len(desired))
+	for _, object := range desired {
+		gvk, err := apiutil.GVKForObject(object, m.Scheme())
+		if err != nil {
+			return err
+		}
+		keep[objectKey{gvk.GroupKind(), client.ObjectKeyFromObject(object)}] = true
+	}
+	return m.eachControlled(ctx, owner, objectTypes, func(object client.Object) error {
+		gvk, err := apiutil.GVKForObject(object, m.Scheme())
+		if err != nil {
+			return err
+		}
+		if keep[objectKey{gvk.GroupKind(), client.ObjectKeyFromObject(object)}] || object.GetDeletionTimestamp() != nil {
+			return nil
+		}
+		return client.IgnoreNotFound(m.Delete(ctx, object))
+	})
+}
+
+// eachControlled calls fn for every object of the given types controlled by
+// owner, listed from the cache through the ControllerField index. Types
+// whose CRD is not installed are skipped.
+func (m *MissionClient) eachControlled(ctx context.Context, owner metav1.Object, objectTypes []client.Object, fn func(client.Object) error) error {
+	for _, objectType := range objectTypes {
+		gvk, err := apiutil.GVKForObject(objectType, m.Scheme())
+		if err != nil {
+			return err
+		}
+		list, err := m.Scheme().New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err != nil {
+			return err
+		}
+		objects, ok := list.(client.ObjectList)
+		if !ok {
+			return fmt.Errorf("%s is not a list type", gvk.Kind+"List")
+		}
+		if err := m.List(ctx, objects, client.MatchingFields{ControllerField: string(owner.GetUID())}); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		err = meta.EachListItem(objects, func(item runtime.Object) error {
+			object := item.(client.Object)
+			if !metav1.IsControlledBy(object, owner) {
+				return nil
+			}
+			return fn(object)
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// InstalledTypes splits objects into the types the cluster serves and the
+// kinds whose CRD is not installed, so that controllers only watch types
+// that exist.
+func InstalledTypes(scheme *runtime.Scheme, mapper meta.RESTMapper, objects []client.Object) ([]client.Object, []string, error) {
+	var installed []client.Object
+	var missing []string
+	for _, object := range objects {
+		gvk, err := apiutil.GVKForObject(object, scheme)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			missing = append(missing, gvk.GroupKind().String())
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		installed = append(installed, object)
+	}
+	return installed, missing, nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	cpv1alpha1 "github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	gcpstoragev1 "github.com/upbound/provider-gcp/apis/storage/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	interceptor "sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+)
+
+func TestInstalledTypes(t *testing.T) {
+	instanceKind := schema.GroupVersionKind{Group: "compute.gcp.upbound.io", Version: "v1beta1", Kind: "Instance"}
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(instanceKind, &gcpcomputev1.Instance{})
+	scheme.AddKnownTypeWithName(schema.GroupVersionKind{Group: "storage.gcp.upbound.io", Version: "v1beta1", Kind: "Bucket"}, &gcpstoragev1.Bucket{})
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(instanceKind, meta.RESTScopeRoot)
+	instance := &gcpcomputev1.Instance{}
+	installed, missing, err := InstalledTypes(scheme, mapper, []client.Object{instance, &gcpstoragev1.Bucket{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(installed, []client.Object{instance}) {
+		t.Errorf("expected only Instances to be installed, got %v", installed)
+	}
+	if !reflect.DeepEqual(missing, []string{"Bucket.storage.gcp.upbound.io"}) {
+		t.Errorf("expected Buckets to be missing, got %v", missing)
+	}
+	if _, _, err := InstalledTypes(runtime.NewScheme(), mapper, []client.Object{instance}); err == nil {
+		t.Error("expected types missing from the scheme to fail")
+	}
+}
+
+func TestDeleteControlled(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{computev1alpha1.AddToScheme, gcpcomputev1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	owner := &computev1alpha1.VirtualMachine{ObjectMeta: metav1.ObjectMeta{Name: "vm", UID: "vm-uid"}}
+	instance := func(name string, controlled bool, finalizers ...string) *gcpcomputev1.Instance {
+		instance := &gcpcomputev1.Instance{ObjectMeta: metav1.ObjectMeta{Name: name, Finalizers: finalizers}}
+		if controlled {
+			if err := controllerutil.SetControllerReference(owner, instance, scheme); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return instance
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(instance("deleted", true), instance("finalized", true, "finalizer.managedresource.crossplane.io"), instance("other", false)).
+		WithIndex(&gcpcomputev1.Instance{}, ControllerField, IndexController).
+		Build()
+	r := &MissionClient{Client: c}
+	ctx := context.Background()
+
+	remaining, err := r.DeleteControlled(ctx, owner, []client.Object{&gcpcomputev1.Instance{}})
+	if err != nil || remaining != 2 {
+		t.Fatalf("expected both controlled instances to remain, got %d, %v", remaining, err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "deleted"}, &gcpcomputev1.Instance{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the instance without finalizer to be deleted, got %v", err)
+	}
+	remaining, err = r.DeleteControlled(ctx, owner, []client.Object{&gcpcomputev1.Instance{}})
+	if err != nil || remaining != 1 {
+		t.Fatalf("expected the finalized instance to remain, got %d, %v", remaining, err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "other"}, &gcpcomputev1.Instance{}); err != nil {
+		t.Errorf("expected the instance of another owner to be kept, got %v", err)
+	}
+}
+
+// newApplyClient returns a client whose server-side applies, which the fake
+// client does not support, are recorded instead.
+func newApplyClient(t *testing.T, objects ...client.Object) (*MissionClient, *[]client.Object) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{missionv1alpha1.AddToScheme, cpv1.AddToScheme, cpv1alpha1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var applied []client.Object
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			applied = append(applied, obj)
+			return nil
+		}}).
+		Build()
+	return &MissionClient{Client: c, Packages: &providers.Packages{}}, &applied
+}
+
+func TestInstallIdentity(t *testing.T) {
+	aws, err := providers.Get("aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	identityKey := func(namespace, name string) *missionv1alpha1.MissionKey {
+		return &missionv1alpha1.MissionKey{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: missionv1alpha1.MissionKeySpec{
+				Type:     "aws",
+				Identity: &missionv1alpha1.WorkloadIdentity{ID: "arn:aws:iam::123456789012:role/" + name},
+			},
+		}
+	}
+	held := &cpv1alpha1.ControllerConfig{ObjectMeta: metav1.ObjectMeta{
+		Name:        "mission-control-aws",
+		Annotations: map[string]string{missionv1alpha1.IdentityKeyAnnotation: "team/other"},
+	}}
+	ctx := context.Background()
+
+	r, applied := newApplyClient(t)
+	if err := r.InstallIdentity(ctx, aws, identityKey("default", "key")); err != nil {
+		t.Fatal(err)
+	}
+	if len(*applied) != 1 {
+		t.Fatalf("expected the ControllerConfig to be applied, got %v", *applied)
+	}
+	config := (*applied)[0].(*cpv1alpha1.ControllerConfig)
+	if config.GetName() != "mission-control-aws" || config.GetAnnotations()["eks.amazonaws.com/role-arn"] != "arn:aws:iam::123456789012:role/key" {
+		t.Errorf("expected the role of the key on mission-control-aws, got %s %v", config.GetName(), config.GetAnnotations())
+	}
+	if config.GetAnnotations()[missionv1alpha1.IdentityKeyAnnotation] != "default/key" || *config.Spec.ServiceAccountName != "mission-control-aws" {
+		t.Errorf("unexpected ControllerConfig %+v", config)
+	}
+
+	r, applied = newApplyClient(t, held, identityKey("team", "other"))
+	err = r.InstallIdentity(ctx, aws, identityKey("default", "key"))
+	if err == nil || !strings.Contains(err.Error(), "team/other") || len(*applied) != 0 {
+		t.Errorf("expected the identity of team/other to be kept, got %v", err)
+	}
+
+	r, applied = newApplyClient(t, held)
+	if err := r.InstallIdentity(ctx, aws, identityKey("default", "key")); err != nil || len(*applied) != 1 {
+		t.Errorf("expected the ControllerConfig of a deleted key to be taken over, got %v", err)
+	}
+
+	r, applied = newApplyClient(t)
+	r.Packages = nil
+	if err := r.InstallIdentity(ctx, aws, identityKey("default", "key")); err != nil || len(*applied) != 0 {
+		t.Errorf("expected nothing to be applied without provider packages, got %v", err)
+	}
+}
+
+func TestInstallPackagesWithIdentity(t *testing.T) {
+	aws, err := providers.Get("aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	r, applied := newApplyClient(t)
+	if err := r.InstallPackages(ctx, aws, aws.Package()); err == nil {
+		t.Error("expected the package not to be healthy yet")
+	}
+	if pkg := (*applied)[0].(*cpv1.Provider); pkg.Spec.ControllerConfigReference != nil {
+		t.Errorf("expected no ControllerConfig, got %v", pkg.Spec.ControllerConfigReference)
+	}
+
+	r, applied = newApplyClient(t, &cpv1alpha1.ControllerConfig{ObjectMeta: metav1.ObjectMeta{Name: "mission-control-aws"}})
+	if err := r.InstallPackages(ctx, aws, aws.Package()); err == nil {
+		t.Error("expected the package not to be healthy yet")
+	}
+	if ref := (*applied)[0].(*cpv1.Provider).Spec.ControllerConfigReference; ref == nil || ref.Name != "mission-control-aws" {
+		t.Errorf("expected the package to run with mission-control-aws, got %v", ref)
+	}
+}
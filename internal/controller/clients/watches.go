@@ -0,0 +1,150 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	cache "sigs.k8s.io/controller-runtime/pkg/cache"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	apiutil "sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	controller "sigs.k8s.io/controller-runtime/pkg/controller"
+	handler "sigs.k8s.io/controller-runtime/pkg/handler"
+	reconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+	source "sigs.k8s.io/controller-runtime/pkg/source"
+
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+)
+
+// ManagedWatches watches the managed resources a controller owns. Only
+// types whose CRD is installed can be watched, so that the controller runs
+// with a subset of the providers. Types installed later by the provider
+// packages of the operator are watched once InstallPackages finds the
+// packages healthy.
+type ManagedWatches struct {
+	name  string
+	owner client.Object
+	kind  string
+
+	scheme  *runtime.Scheme
+	mapper  meta.RESTMapper
+	cache   cache.Cache
+	indexer client.FieldIndexer
+	log     logr.Logger
+
+	mu         sync.Mutex
+	controller controller.Controller
+	watched    map[schema.GroupVersionKind]bool
+}
+
+// NewManagedWatches returns the watches of the named controller of owner on
+// the managed resources of kind.
+func NewManagedWatches(mgr ctrl.Manager, name string, owner client.Object, kind string) *ManagedWatches {
+	return &ManagedWatches{
+		name:    name,
+		owner:   owner,
+		kind:    kind,
+		scheme:  mgr.GetScheme(),
+		mapper:  mgr.GetRESTMapper(),
+		cache:   mgr.GetCache(),
+		indexer: mgr.GetFieldIndexer(),
+		log:     mgr.GetLogger(),
+		watched: map[schema.GroupVersionKind]bool{},
+	}
+}
+
+// Complete adds the installed types to builder as owned types and builds
+// the controller, which later watches are added to.
+func (w *ManagedWatches) Complete(builder *ctrl.Builder, r reconcile.Reconciler) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	installed, missing, err := w.unwatched(providers.ManagedTypes(w.kind))
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		w.log.Info("not watching resources whose CRD is not installed yet", "controller", w.name, "kinds", missing)
+	}
+	for _, object := range installed {
+		builder = builder.Owns(object)
+	}
+	c, err := builder.Build(r)
+	if err != nil {
+		return err
+	}
+	w.controller = c
+	for _, object := range installed {
+		gvk, err := apiutil.GVKForObject(object, w.scheme)
+		if err != nil {
+			return err
+		}
+		w.watched[gvk] = true
+	}
+	return nil
+}
+
+// WatchInstalled watches the types of provider p installed since the
+// controller started, indexing them by their controller first.
+func (w *ManagedWatches) WatchInstalled(ctx context.Context, p providers.Provider) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.controller == nil {
+		return nil
+	}
+	installed, _, err := w.unwatched(p.ManagedTypes(w.kind))
+	if err != nil || len(installed) == 0 {
+		return err
+	}
+	if err := IndexControlled(ctx, w.indexer, w.scheme, installed); err != nil {
+		return err
+	}
+	owned := handler.EnqueueRequestForOwner(w.scheme, w.mapper, w.owner, handler.OnlyControllerOwner())
+	for _, object := range installed {
+		gvk, err := apiutil.GVKForObject(object, w.scheme)
+		if err != nil {
+			return err
+		}
+		if err := w.controller.Watch(source.Kind(w.cache, object), owned); err != nil {
+			return err
+		}
+		w.watched[gvk] = true
+		w.log.Info("watching resources whose CRD got installed", "controller", w.name, "kind", gvk.GroupKind().String())
+	}
+	return nil
+}
+
+// unwatched splits the types that are not watched yet into the installed
+// types and the kinds whose CRD is missing.
+func (w *ManagedWatches) unwatched(types []client.Object) ([]client.Object, []string, error) {
+	var objects []client.Object
+	for _, object := range types {
+		gvk, err := apiutil.GVKForObject(object, w.scheme)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !w.watched[gvk] {
+			objects = append(objects, object)
+		}
+	}
+	return InstalledTypes(w.scheme, w.mapper, objects)
+}
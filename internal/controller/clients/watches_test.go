@@ -0,0 +1,102 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controller "sigs.k8s.io/controller-runtime/pkg/controller"
+	handler "sigs.k8s.io/controller-runtime/pkg/handler"
+	predicate "sigs.k8s.io/controller-runtime/pkg/predicate"
+	source "sigs.k8s.io/controller-runtime/pkg/source"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+)
+
+// watchRecorder records the watches added to a controller.
+type watchRecorder struct {
+	controller.Controller
+	watches int
+}
+
+func (c *watchRecorder) Watch(source.Source, handler.EventHandler, ...predicate.Predicate) error {
+	c.watches++
+	return nil
+}
+
+// indexRecorder records the fields indexed on each type.
+type indexRecorder struct {
+	indexed []string
+}
+
+func (i *indexRecorder) IndexField(_ context.Context, _ client.Object, field string, _ client.IndexerFunc) error {
+	i.indexed = append(i.indexed, field)
+	return nil
+}
+
+func TestWatchInstalled(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{computev1alpha1.AddToScheme, gcpcomputev1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	recorder, indexer := &watchRecorder{}, &indexRecorder{}
+	w := &ManagedWatches{
+		name:       "VirtualMachine",
+		owner:      &computev1alpha1.VirtualMachine{},
+		kind:       providers.KindVirtualMachine,
+		scheme:     scheme,
+		mapper:     mapper,
+		indexer:    indexer,
+		log:        logr.Discard(),
+		controller: recorder,
+		watched:    map[schema.GroupVersionKind]bool{},
+	}
+	gcp, err := providers.Get("gcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := w.WatchInstalled(ctx, gcp); err != nil {
+		t.Fatal(err)
+	}
+	if recorder.watches != 0 {
+		t.Errorf("expected no watches before the CRD is installed, got %d", recorder.watches)
+	}
+	mapper.Add(schema.GroupVersionKind{Group: "compute.gcp.upbound.io", Version: "v1beta1", Kind: "Instance"}, meta.RESTScopeRoot)
+	for i := 0; i < 2; i++ {
+		if err := w.WatchInstalled(ctx, gcp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if recorder.watches != 1 {
+		t.Errorf("expected Instances to be watched once, got %d watches", recorder.watches)
+	}
+	if len(indexer.indexed) != 1 || indexer.indexed[0] != ControllerField {
+		t.Errorf("expected Instances to be indexed by controller once, got %v", indexer.indexed)
+	}
+}
@@ -18,29 +18,32 @@ package compute
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"reflect"
+	"time"
 
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
 	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	cpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
 	v1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
-	awscomputev1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
-	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
 )
 
 func (r *VirtualMachineReconciler) ReconcileVirtualMachine(ctx context.Context, mission *v1alpha1.Mission, vm *computev1alpha1.VirtualMachine) error {
-	keyName := vm.Spec.MissionRef.MissionKey
-	missionKey, err := r.GetMissionKey(ctx, mission, keyName)
+	pkg, err := mission.GetPackage(vm.Spec.Provider, vm.Spec.MissionRef.MissionKey)
 	if err != nil {
+		r.Recorder.Event(vm, "Warning", "Package not found", err.Error())
 		return err
 	}
-	err = r.ReconcileVirtualMachineByProvider(ctx, mission, missionKey, vm)
+	missionKey, err := r.GetMissionKey(ctx, mission, pkg.Credentials.Name)
+	if err != nil {
+		return err
+	}
+	err = r.ReconcileVirtualMachineByProvider(ctx, mission, pkg, missionKey, vm)
 	if err != nil {
 		r.Recorder.Event(mission, "Warning", "ProviderConfig not created", "Could not correctly create ProviderConfig resource.")
 		return err
@@ -48,90 +51,93 @@ func (r *VirtualMachineReconciler) ReconcileVirtualMachine(ctx context.Context,
 	return nil
 }
 
-func (r *VirtualMachineReconciler) ReconcileVirtualMachineByProvider(ctx context.Context, mission *v1alpha1.Mission, missionKey *v1alpha1.MissionKey, vm *computev1alpha1.VirtualMachine) error {
-	var err error
-	pkg := mission.Spec.Packages[0]
-	if pkg.Provider == "gcp" {
-		err = r.GetVirtualMachineGCP(ctx, mission, vm)
-	} else if pkg.Provider == "aws" {
-		err = r.GetVirtualMachineAWS(ctx, mission, vm)
-	} else {
-		message := fmt.Sprintf("Provider %s not known", pkg.Provider)
-		err = errors.New(message)
+func (r *VirtualMachineReconciler) ReconcileVirtualMachineByProvider(ctx context.Context, mission *v1alpha1.Mission, pkg *v1alpha1.PackageConfig, missionKey *v1alpha1.MissionKey, vm *computev1alpha1.VirtualMachine) error {
+	provider, err := providers.Get(pkg.Provider)
+	if err != nil {
+		return err
+	}
+	providerConfig := provider.ProviderConfig(mission, pkg, missionKey)
+	// The ProviderConfig CRD comes with the provider packages, so they are
+	// installed before the ProviderConfig is looked for.
+	if err := r.InstallPackages(ctx, provider, provider.ServicePackages(providers.KindVirtualMachine)...); err != nil {
+		r.Recorder.Event(vm, "Warning", "Provider package not ready", err.Error())
+		return err
+	}
+	if err := r.ConfirmProviderConfig(ctx, providerConfig); err != nil {
+		r.Recorder.Event(vm, "Warning", "ProviderConfig not found", err.Error())
+		return err
+	}
+	resolved, err := r.ResolveNetwork(ctx, provider, vm)
+	if err != nil {
+		r.Recorder.Event(vm, "Warning", "Network not usable", err.Error())
+		return err
 	}
+	objects, err := provider.VirtualMachine(providerConfig.GetName(), resolved)
 	if err != nil {
 		return err
 	}
+	policy := vm.Spec.DeletionPolicy
+	if policy == "" {
+		policy = mission.Spec.DeletionPolicy
+	}
+	observed := make([]client.Object, 0, len(objects))
+	for _, object := range objects {
+		utils.SetDeletionPolicy(object, policy)
+		if err := r.ApplyObject(ctx, vm, object); err != nil {
+			return err
+		}
+		observed = append(observed, object)
+	}
+	vm.Status.Provider = provider.Name()
+	provider.ObserveVirtualMachine(vm, observed)
 	return nil
 }
 
-func (r *VirtualMachineReconciler) GetVirtualMachineGCP(ctx context.Context, mission *v1alpha1.Mission, vm *computev1alpha1.VirtualMachine) error {
-	// Create virtual machine config
-	currentgcpvm := gcpcomputev1.Instance{}
-	gcpvm := gcpcomputev1.Instance{
-		ObjectMeta: v1.ObjectMeta{
-			Name: vm.Spec.ForProvider.Name,
-		},
-		Spec: gcpcomputev1.InstanceSpec{
-			ForProvider: gcpcomputev1.InstanceParameters{
-				Zone:        &vm.Spec.ForProvider.Zone,
-				MachineType: &vm.Spec.ForProvider.MachineType,
-				BootDisk: []gcpcomputev1.BootDiskParameters{{
-					InitializeParams: []gcpcomputev1.InitializeParamsParameters{{
-						Image: &vm.Spec.ForProvider.Image,
-					}},
-				}},
-				NetworkInterface: []gcpcomputev1.NetworkInterfaceParameters{{
-					Network: &vm.Spec.ForProvider.Network,
-				}},
-			},
-			ResourceSpec: cpcommonv1.ResourceSpec{
-				ProviderConfigReference: &cpcommonv1.Reference{
-					Name: "gcloud-provider",
-				},
-			},
-		},
-	}
-	if err := controllerutil.SetControllerReference(vm, &gcpvm, r.Scheme); err != nil {
-		return err
+// ResolveNetwork returns the machine to translate for the provider. When it
+// references a Network, the reference of the returned copy names the cloud
+// resources of the Network and the subnet the machine is placed in.
+func (r *VirtualMachineReconciler) ResolveNetwork(ctx context.Context, provider providers.Provider, vm *computev1alpha1.VirtualMachine) (*computev1alpha1.VirtualMachine, error) {
+	ref := vm.Spec.ForProvider.NetworkRef
+	if ref == nil {
+		return vm, nil
+	}
+	network := &networkv1alpha1.Network{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name}, network); err != nil {
+		return nil, err
+	}
+	if network.Spec.MissionRef.MissionName != vm.Spec.MissionRef.MissionName {
+		return nil, fmt.Errorf("Network %s does not belong to Mission %s", ref.Name, vm.Spec.MissionRef.MissionName)
 	}
-	err := r.Get(ctx, types.NamespacedName{Name: vm.Spec.ForProvider.Name}, &currentgcpvm)
+	if network.Status.Provider != "" && network.Status.Provider != provider.Name() {
+		return nil, fmt.Errorf("Network %s is created on %s, not %s", ref.Name, network.Status.Provider, provider.Name())
+	}
+	subnet, err := network.GetSubnet(ref.Subnet)
 	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			return r.Create(ctx, &gcpvm)
-		}
-		return err
+		return nil, err
 	}
-	if reflect.DeepEqual(currentgcpvm.Spec, gcpvm.Spec) {
-		return nil
+	resolved := vm.DeepCopy()
+	resolved.Spec.ForProvider.NetworkRef = &computev1alpha1.NetworkReference{
+		Name:   network.Spec.ForProvider.Name,
+		Subnet: subnet.Name,
 	}
-	return r.Update(ctx, &currentgcpvm)
+	return resolved, nil
 }
 
-func (r *VirtualMachineReconciler) GetVirtualMachineAWS(ctx context.Context, mission *v1alpha1.Mission, vm *computev1alpha1.VirtualMachine) error {
-	// Create virtual machine config
-	currentawsvm := awscomputev1.Instance{}
-	awsvm := awscomputev1.Instance{
-		ObjectMeta: v1.ObjectMeta{
-			Name: vm.Spec.ForProvider.Name,
-		},
-		Spec: awscomputev1.InstanceSpec{
-			ForProvider:  awscomputev1.InstanceParameters{},
-			ResourceSpec: cpcommonv1.ResourceSpec{},
-		},
-	}
-	if err := controllerutil.SetControllerReference(vm, &awsvm, r.Scheme); err != nil {
-		return err
+// DeleteVirtualMachine removes the managed resources of the VirtualMachine and releases its
+// finalizer once all of them are gone. Whether the cloud resources survive
+// is decided by the deletion policy set on each managed resource.
+func (r *VirtualMachineReconciler) DeleteVirtualMachine(ctx context.Context, vm *computev1alpha1.VirtualMachine) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(vm, utils.Finalizer) {
+		return ctrl.Result{}, nil
 	}
-	err := r.Get(ctx, types.NamespacedName{Name: vm.Spec.ForProvider.Name}, &currentawsvm)
+	remaining, err := r.DeleteControlled(ctx, vm, providers.ManagedTypes(providers.KindVirtualMachine))
 	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			return r.Create(ctx, &awsvm)
-		}
-		return err
+		return ctrl.Result{}, err
 	}
-	if reflect.DeepEqual(currentawsvm.Spec, awsvm.Spec) {
-		return nil
+	if remaining > 0 {
+		r.Recorder.Event(vm, "Normal", "Deleting", fmt.Sprintf("Waiting for %d managed resources to be deleted", remaining))
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
-	return r.Update(ctx, &currentawsvm)
+	controllerutil.RemoveFinalizer(vm, utils.Finalizer)
+	return ctrl.Result{}, r.Update(ctx, vm)
 }
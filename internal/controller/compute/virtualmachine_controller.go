@@ -19,13 +19,19 @@ package compute
 import (
 	"context"
 
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	record "k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	handler "sigs.k8s.io/controller-runtime/pkg/handler"
 
 	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
 	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
-	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
 )
 
 type VirtualMachineReconciler struct {
@@ -37,25 +43,51 @@ type VirtualMachineReconciler struct {
 //+kubebuilder:rbac:groups=compute.mission-control.apis.io,resources=virtualmachines,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=compute.mission-control.apis.io,resources=virtualmachines/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=compute.mission-control.apis.io,resources=virtualmachines/finalizers,verbs=update
+//+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missions;missionkeys,verbs=get;list;watch
+//+kubebuilder:rbac:groups=network.mission-control.apis.io,resources=networks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=pkg.crossplane.io,resources=providers;controllerconfigs,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=compute.gcp.upbound.io;ec2.aws.upbound.io,resources=instances,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=azure.upbound.io,resources=resourcegroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=network.azure.upbound.io,resources=networkinterfaces,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=compute.azure.upbound.io,resources=linuxvirtualmachines,verbs=get;list;watch;create;update;patch;delete
 
 func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	vm := &computev1alpha1.VirtualMachine{}
 	err := r.Get(ctx, req.NamespacedName, vm)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !vm.GetDeletionTimestamp().IsZero() {
+		return r.DeleteVirtualMachine(ctx, vm)
+	}
+	if controllerutil.AddFinalizer(vm, utils.Finalizer) {
+		if err := r.Update(ctx, vm); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	mission, err := r.GetMission(ctx, vm.Spec.MissionRef.MissionName)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, utils.UpdateStatus(ctx, r, vm, &vm.Status.ConditionedStatus, err)
 	}
 	err = r.ReconcileVirtualMachine(ctx, mission, vm)
-	return ctrl.Result{}, err
+	return ctrl.Result{}, utils.UpdateStatus(ctx, r, vm, &vm.Status.ConditionedStatus, err)
 }
 
 func (r *VirtualMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// VirtualMachines are reconciled again when their Mission or one of its
+	// MissionKeys changes, found through the indexes of clients.SetupIndexes.
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&computev1alpha1.VirtualMachine{}).
-		Owns(&gcpcomputev1.Instance{}).
-		Complete(r)
+		Watches(&missionv1alpha1.Mission{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForMissionRef(&computev1alpha1.VirtualMachineList{}))).
+		Watches(&missionv1alpha1.MissionKey{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForMissionRefByKey(&computev1alpha1.VirtualMachineList{})))
+	// Provider packages are only watched when the operator installs them,
+	// as their CRD is not required otherwise.
+	if r.Packages != nil {
+		builder = builder.Watches(&cpv1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForPackage(&computev1alpha1.VirtualMachineList{})))
+	}
+	// Managed resources are only watched when their CRD is installed, and
+	// once the provider packages installed by the operator add it.
+	r.Managed = clients.NewManagedWatches(mgr, "VirtualMachine", &computev1alpha1.VirtualMachine{}, providers.KindVirtualMachine)
+	return r.Managed.Complete(builder, r)
 }
@@ -0,0 +1,358 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awscomputev1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	azurev1 "github.com/upbound/provider-azure/apis/azure/v1beta1"
+	azurecomputev1 "github.com/upbound/provider-azure/apis/compute/v1beta1"
+	azurenetworkv1 "github.com/upbound/provider-azure/apis/network/v1beta1"
+	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	types "k8s.io/apimachinery/pkg/types"
+	record "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+var _ = Describe("VirtualMachine AWS translation", func() {
+	Context("Converting a stored VirtualMachine to an EC2 Instance", func() {
+		It("Should map zone, image, machine type and network", func() {
+			By("Creating new aws virtual machine")
+			ctx := context.Background()
+			vm := &computev1alpha1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "virtualmachine-sample-aws",
+				},
+				Spec: computev1alpha1.VirtualMachineSpec{
+					MissionRef: computev1alpha1.VirtualMachineMissionRef{
+						MissionName: "mission-sample-aws",
+						MissionKey:  "missionkey-sample-aws",
+					},
+					ForProvider: computev1alpha1.ProviderData{
+						Name:        "samplevm-aws",
+						Zone:        "us-east-1a",
+						MachineType: "t3.micro",
+						Image:       "ami-0123456789abcdef0",
+						Network:     "subnet-0123456789abcdef0",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, vm)).Should(Succeed())
+
+			stored := &computev1alpha1.VirtualMachine{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: vm.GetName()}, stored)).Should(Succeed())
+			instance := stored.Convert2AWS(missionv1alpha1.ProviderConfigName(stored.Spec.MissionRef.MissionName, "aws"))
+			Expect(instance.GetName()).To(Equal("samplevm-aws"))
+			Expect(*instance.Spec.ForProvider.Region).To(Equal("us-east-1"))
+			Expect(*instance.Spec.ForProvider.AvailabilityZone).To(Equal("us-east-1a"))
+			Expect(*instance.Spec.ForProvider.AMI).To(Equal("ami-0123456789abcdef0"))
+			Expect(*instance.Spec.ForProvider.InstanceType).To(Equal("t3.micro"))
+			Expect(*instance.Spec.ForProvider.SubnetID).To(Equal("subnet-0123456789abcdef0"))
+			Expect(instance.Spec.ProviderConfigReference.Name).To(Equal("mission-sample-aws-aws"))
+		})
+		It("Should use a region and the default VPC when no zone or subnet is given", func() {
+			By("Creating new aws virtual machine without zone")
+			ctx := context.Background()
+			vm := &computev1alpha1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "virtualmachine-sample-aws-region",
+				},
+				Spec: computev1alpha1.VirtualMachineSpec{
+					MissionRef: computev1alpha1.VirtualMachineMissionRef{
+						MissionName: "mission-sample-aws",
+					},
+					ForProvider: computev1alpha1.ProviderData{
+						Name:        "samplevm-aws-region",
+						Zone:        "eu-west-1",
+						MachineType: "t3.micro",
+						Image:       "ami-0123456789abcdef0",
+						Network:     "default",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, vm)).Should(Succeed())
+
+			stored := &computev1alpha1.VirtualMachine{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: vm.GetName()}, stored)).Should(Succeed())
+			instance := stored.Convert2AWS(missionv1alpha1.ProviderConfigName(stored.Spec.MissionRef.MissionName, "aws"))
+			Expect(*instance.Spec.ForProvider.Region).To(Equal("eu-west-1"))
+			Expect(instance.Spec.ForProvider.AvailabilityZone).To(BeNil())
+			Expect(instance.Spec.ForProvider.SubnetID).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("VirtualMachine GCP translation", func() {
+	Context("Converting a VirtualMachine to a Compute Engine Instance", func() {
+		It("Should reference the ProviderConfig created by the mission", func() {
+			mission := &missionv1alpha1.Mission{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mission-sample-gcp",
+				},
+				Spec: missionv1alpha1.MissionSpec{
+					Packages: []missionv1alpha1.PackageConfig{{
+						Provider:  "gcp",
+						ProjectID: "made-up-project-id",
+					}},
+				},
+			}
+			vm := &computev1alpha1.VirtualMachine{
+				Spec: computev1alpha1.VirtualMachineSpec{
+					ForProvider: computev1alpha1.ProviderData{
+						Name: "samplevm-gcp",
+					},
+				},
+			}
+			providerConfig := mission.Convert2GCP(&mission.Spec.Packages[0], nil)
+			instance := vm.Convert2GCP(providerConfig.GetName())
+			Expect(instance.Spec.ProviderConfigReference.Name).To(Equal("mission-sample-gcp-gcp"))
+		})
+		It("Should place the machine in the subnetwork of a referenced Network", func() {
+			vm := &computev1alpha1.VirtualMachine{
+				Spec: computev1alpha1.VirtualMachineSpec{
+					ForProvider: computev1alpha1.ProviderData{
+						Name:       "samplevm-gcp",
+						NetworkRef: &computev1alpha1.NetworkReference{Name: "network-sample", Subnet: "web"},
+					},
+				},
+			}
+			nic := vm.Convert2GCP("mission-sample-gcp-gcp").Spec.ForProvider.NetworkInterface[0]
+			Expect(nic.Network).To(BeNil())
+			Expect(nic.NetworkRef.Name).To(Equal("network-sample"))
+			Expect(nic.SubnetworkRef.Name).To(Equal("network-sample-web"))
+
+			instance := vm.Convert2AWS("mission-sample-aws-aws")
+			Expect(instance.Spec.ForProvider.SubnetIDRef.Name).To(Equal("network-sample-web"))
+			Expect(instance.Spec.ForProvider.VPCSecurityGroupIDRefs[0].Name).To(Equal("network-sample"))
+		})
+	})
+})
+
+var _ = Describe("VirtualMachine Azure translation", func() {
+	Context("Converting a VirtualMachine to a Linux virtual machine", func() {
+		azureVM := func() *computev1alpha1.VirtualMachine {
+			return &computev1alpha1.VirtualMachine{
+				Spec: computev1alpha1.VirtualMachineSpec{
+					ForProvider: computev1alpha1.ProviderData{
+						Name:         "samplevm-azure",
+						Zone:         "eastus",
+						MachineType:  "Standard_B1s",
+						Image:        "Canonical:0001-com-ubuntu-server-jammy:22_04-lts:latest",
+						Network:      "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/default",
+						SSHPublicKey: "ssh-ed25519 AAAA",
+					},
+				},
+			}
+		}
+		It("Should create the machine in a resource group of its own", func() {
+			vm := azureVM()
+			Expect(vm.AzureVerify()).To(Succeed())
+			resourceGroup, nic, linuxvm := vm.Convert2Azure("mission-sample-azure-azure")
+			Expect(resourceGroup.GetName()).To(Equal("virtualmachine-samplevm-azure"))
+			Expect(*resourceGroup.Spec.ForProvider.Location).To(Equal("eastus"))
+			Expect(nic.Spec.ForProvider.ResourceGroupNameRef.Name).To(Equal("virtualmachine-samplevm-azure"))
+			Expect(*nic.Spec.ForProvider.IPConfiguration[0].SubnetID).To(Equal(vm.Spec.ForProvider.Network))
+			params := linuxvm.Spec.ForProvider
+			Expect(params.ResourceGroupNameRef.Name).To(Equal("virtualmachine-samplevm-azure"))
+			Expect(params.NetworkInterfaceIdsRefs[0].Name).To(Equal("samplevm-azure-nic"))
+			Expect(*params.Size).To(Equal("Standard_B1s"))
+			Expect(*params.AdminSSHKey[0].PublicKey).To(Equal("ssh-ed25519 AAAA"))
+			Expect(*params.SourceImageReference[0].Publisher).To(Equal("Canonical"))
+			Expect(*params.SourceImageReference[0].Version).To(Equal("latest"))
+			Expect(linuxvm.Spec.ProviderConfigReference.Name).To(Equal("mission-sample-azure-azure"))
+		})
+		It("Should not use the defaulted network as a subnet id", func() {
+			vm := azureVM()
+			vm.Spec.ForProvider.Network = computev1alpha1.DefaultNetwork
+			_, nic, _ := vm.Convert2Azure("mission-sample-azure-azure")
+			Expect(nic.Spec.ForProvider.IPConfiguration[0].SubnetID).To(BeNil())
+		})
+		It("Should reject machines without an SSH key or an image URN", func() {
+			vm := azureVM()
+			vm.Spec.ForProvider.SSHPublicKey = ""
+			Expect(vm.AzureVerify()).NotTo(Succeed())
+
+			vm = azureVM()
+			vm.Spec.ForProvider.Image = "ubuntu-2204"
+			Expect(vm.AzureVerify()).NotTo(Succeed())
+		})
+	})
+})
+
+var _ = Describe("VirtualMachine status", func() {
+	Context("Reconciling a VirtualMachine", func() {
+		var (
+			ctx = context.Background()
+			c   client.Client
+			r   *VirtualMachineReconciler
+		)
+		newVM := func(name string) *computev1alpha1.VirtualMachine {
+			return &computev1alpha1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: computev1alpha1.VirtualMachineSpec{
+					MissionRef:  computev1alpha1.VirtualMachineMissionRef{MissionName: "mission-sample-missing"},
+					ForProvider: computev1alpha1.ProviderData{Name: name},
+				},
+			}
+		}
+		reconcile := func(vm *computev1alpha1.VirtualMachine) *computev1alpha1.VirtualMachine {
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(vm)})
+			Expect(err).To(HaveOccurred())
+			stored := &computev1alpha1.VirtualMachine{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(vm), stored)).To(Succeed())
+			return stored
+		}
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(computev1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(missionv1alpha1.AddToScheme(scheme)).To(Succeed())
+			c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&computev1alpha1.VirtualMachine{}).Build()
+			r = &VirtualMachineReconciler{
+				MissionClient: clients.MissionClient{Client: c},
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+			}
+		})
+
+		It("Should report a failed reconcile through the status subresource", func() {
+			vm := newVM("virtualmachine-sample-status")
+			Expect(c.Create(ctx, vm)).To(Succeed())
+
+			stored := reconcile(vm)
+			Expect(stored.GetFinalizers()).To(ContainElement(utils.Finalizer))
+			synced := stored.Status.GetCondition(xpv1.TypeSynced)
+			Expect(synced.Status).To(Equal(corev1.ConditionFalse))
+			Expect(synced.Reason).To(Equal(xpv1.ReasonReconcileError))
+			Expect(synced.Message).To(ContainSubstring("mission-sample-missing"))
+			ready := stored.Status.GetCondition(xpv1.TypeReady)
+			Expect(ready.Status).To(Equal(corev1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(xpv1.ReasonUnavailable))
+		})
+		It("Should keep the mirrored Ready condition when a later reconcile fails", func() {
+			vm := newVM("virtualmachine-sample-status-ready")
+			Expect(c.Create(ctx, vm)).To(Succeed())
+			vm.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			Expect(c.Status().Update(ctx, vm)).To(Succeed())
+
+			stored := reconcile(vm)
+			Expect(stored.Status.GetCondition(xpv1.TypeSynced).Reason).To(Equal(xpv1.ReasonReconcileError))
+			Expect(stored.Status.GetCondition(xpv1.TypeReady).Status).To(Equal(corev1.ConditionTrue))
+		})
+	})
+	Context("Observing an EC2 Instance", func() {
+		It("Should store the instance details and conditions", func() {
+			instance := &awscomputev1.Instance{}
+			instance.Status.AtProvider = awscomputev1.InstanceObservation{
+				ID:            utils.StrPtr("i-0123456789abcdef0"),
+				InstanceState: utils.StrPtr("running"),
+				PrivateIP:     utils.StrPtr("10.0.0.4"),
+				PublicIP:      utils.StrPtr("54.0.0.4"),
+			}
+			instance.Status.SetConditions(xpv1.Available())
+			vm := &computev1alpha1.VirtualMachine{}
+			vm.ObserveAWS(instance)
+			Expect(vm.Status.InstanceID).To(Equal("i-0123456789abcdef0"))
+			Expect(vm.Status.State).To(Equal("running"))
+			Expect(vm.Status.InternalIP).To(Equal("10.0.0.4"))
+			Expect(vm.Status.ExternalIP).To(Equal("54.0.0.4"))
+			Expect(vm.Status.GetCondition(xpv1.TypeReady).Status).To(Equal(corev1.ConditionTrue))
+			Expect(vm.Status.GetCondition(xpv1.TypeSynced).Status).To(Equal(corev1.ConditionTrue))
+		})
+		It("Should report a machine that is still being created", func() {
+			vm := &computev1alpha1.VirtualMachine{}
+			vm.ObserveAWS(&awscomputev1.Instance{})
+			Expect(vm.Status.GetCondition(xpv1.TypeReady).Reason).To(Equal(xpv1.ReasonCreating))
+		})
+	})
+})
+
+var _ = Describe("VirtualMachine deletion", func() {
+	Context("Deleting a VirtualMachine with managed resources", func() {
+		It("Should keep the finalizer until the managed resources are gone", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			for _, add := range []func(*runtime.Scheme) error{
+				computev1alpha1.AddToScheme, gcpcomputev1.AddToScheme, awscomputev1.AddToScheme,
+				azurev1.AddToScheme, azurenetworkv1.AddToScheme, azurecomputev1.AddToScheme,
+			} {
+				Expect(add(scheme)).To(Succeed())
+			}
+			now := metav1.Now()
+			vm := &computev1alpha1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "virtualmachine-sample-deletion",
+					UID:               "virtualmachine-sample-deletion",
+					Finalizers:        []string{utils.Finalizer},
+					DeletionTimestamp: &now,
+				},
+			}
+			instance := &gcpcomputev1.Instance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "samplevm-deletion",
+					Finalizers: []string{"finalizer.managedresource.crossplane.io"},
+				},
+			}
+			Expect(controllerutil.SetControllerReference(vm, instance, scheme)).To(Succeed())
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vm, instance)
+			for _, object := range providers.ManagedTypes(providers.KindVirtualMachine) {
+				builder = builder.WithIndex(object, clients.ControllerField, clients.IndexController)
+			}
+			c := builder.Build()
+			r := &VirtualMachineReconciler{
+				MissionClient: clients.MissionClient{Client: c},
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+			}
+
+			By("Deleting the managed instance first")
+			result, err := r.DeleteVirtualMachine(ctx, vm)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+			stored := &computev1alpha1.VirtualMachine{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(vm), stored)).To(Succeed())
+			Expect(stored.GetFinalizers()).To(ContainElement(utils.Finalizer))
+			deleting := &gcpcomputev1.Instance{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(instance), deleting)).To(Succeed())
+			Expect(deleting.GetDeletionTimestamp()).NotTo(BeNil())
+
+			By("Releasing the finalizer once Crossplane removed the instance")
+			deleting.SetFinalizers(nil)
+			Expect(c.Update(ctx, deleting)).To(Succeed())
+			result, err = r.DeleteVirtualMachine(ctx, stored)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(k8serrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(vm), stored))).To(BeTrue())
+		})
+	})
+})
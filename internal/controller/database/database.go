@@ -0,0 +1,151 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	v1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// passwordLength is the length of the generated administrator passwords.
+const passwordLength = 24
+
+func (r *DatabaseReconciler) ReconcileDatabase(ctx context.Context, mission *v1alpha1.Mission, database *databasev1alpha1.Database) error {
+	pkg, err := mission.GetPackage(database.Spec.Provider, database.Spec.MissionRef.MissionKey)
+	if err != nil {
+		r.Recorder.Event(database, "Warning", "Package not found", err.Error())
+		return err
+	}
+	missionKey, err := r.GetMissionKey(ctx, mission, pkg.Credentials.Name)
+	if err != nil {
+		return err
+	}
+	err = r.ReconcileDatabaseByProvider(ctx, mission, pkg, missionKey, database)
+	if err != nil {
+		r.Recorder.Event(mission, "Warning", "ProviderConfig not created", "Could not correctly create ProviderConfig resource.")
+		return err
+	}
+	return nil
+}
+
+// ReconcileDatabaseByProvider applies the managed resources of the database
+// after its connection Secret, as they read the administrator password from
+// it. The Secret is applied again once the endpoint of the database is
+// known.
+func (r *DatabaseReconciler) ReconcileDatabaseByProvider(ctx context.Context, mission *v1alpha1.Mission, pkg *v1alpha1.PackageConfig, missionKey *v1alpha1.MissionKey, database *databasev1alpha1.Database) error {
+	provider, err := providers.Get(pkg.Provider)
+	if err != nil {
+		return err
+	}
+	providerConfig := provider.ProviderConfig(mission, pkg, missionKey)
+	// The ProviderConfig CRD comes with the provider packages, so they are
+	// installed before the ProviderConfig is looked for.
+	if err := r.InstallPackages(ctx, provider, provider.ServicePackages(providers.KindDatabase)...); err != nil {
+		r.Recorder.Event(database, "Warning", "Provider package not ready", err.Error())
+		return err
+	}
+	if err := r.ConfirmProviderConfig(ctx, providerConfig); err != nil {
+		r.Recorder.Event(database, "Warning", "ProviderConfig not found", err.Error())
+		return err
+	}
+	objects, err := provider.Database(providerConfig.GetName(), database)
+	if err != nil {
+		return err
+	}
+	password, err := r.GetPassword(ctx, database)
+	if err != nil {
+		r.Recorder.Event(database, "Warning", "Connection secret not usable", err.Error())
+		return err
+	}
+	if err := r.ApplyObject(ctx, database, database.ConnectionSecret(password)); err != nil {
+		return err
+	}
+	policy := database.Spec.DeletionPolicy
+	if policy == "" {
+		policy = mission.Spec.DeletionPolicy
+	}
+	observed := make([]client.Object, 0, len(objects))
+	for _, object := range objects {
+		utils.SetDeletionPolicy(object, policy)
+		if err := r.ApplyObject(ctx, database, object); err != nil {
+			return err
+		}
+		observed = append(observed, object)
+	}
+	database.Status.Provider = provider.Name()
+	provider.ObserveDatabase(database, observed)
+	return r.ApplyObject(ctx, database, database.ConnectionSecret(password))
+}
+
+// GetPassword returns the administrator password kept in the connection
+// Secret of the database, or a new one when there is no Secret yet. The
+// Secret is read past the cache, as a new password for a Secret the cache
+// has not seen yet would replace the one the database was created with.
+// Secrets the database does not control are never taken over.
+func (r *DatabaseReconciler) GetPassword(ctx context.Context, database *databasev1alpha1.Database) (string, error) {
+	ref := database.Spec.ConnectionSecretRef
+	secret := &corev1.Secret{}
+	err := r.APIReader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret)
+	if k8serrors.IsNotFound(err) {
+		return utils.GeneratePassword(passwordLength)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !metav1.IsControlledBy(secret, database) {
+		return "", fmt.Errorf("Secret %s/%s already exists and is not owned by Database %s", ref.Namespace, ref.Name, database.GetName())
+	}
+	password := secret.Data[xpv1.ResourceCredentialsSecretPasswordKey]
+	if len(password) == 0 {
+		return "", fmt.Errorf("Secret %s/%s of Database %s holds no password", ref.Namespace, ref.Name, database.GetName())
+	}
+	return string(password), nil
+}
+
+// DeleteDatabase removes the managed resources of the Database and releases
+// its finalizer once all of them are gone. The connection Secret is owned by
+// the Database and garbage collected along with it.
+func (r *DatabaseReconciler) DeleteDatabase(ctx context.Context, database *databasev1alpha1.Database) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(database, utils.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+	remaining, err := r.DeleteControlled(ctx, database, providers.ManagedTypes(providers.KindDatabase))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if remaining > 0 {
+		r.Recorder.Event(database, "Normal", "Deleting", fmt.Sprintf("Waiting for %d managed resources to be deleted", remaining))
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	controllerutil.RemoveFinalizer(database, utils.Finalizer)
+	return ctrl.Result{}, r.Update(ctx, database)
+}
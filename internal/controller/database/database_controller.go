@@ -0,0 +1,100 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	corev1 "k8s.io/api/core/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	record "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	handler "sigs.k8s.io/controller-runtime/pkg/handler"
+
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// DatabaseReconciler reconciles a Database object
+type DatabaseReconciler struct {
+	clients.MissionClient
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// APIReader reads connection Secrets past the cache, so that a Secret
+	// written moments ago is not taken for a missing one.
+	APIReader client.Reader
+}
+
+//+kubebuilder:rbac:groups=database.mission-control.apis.io,resources=databases,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=database.mission-control.apis.io,resources=databases/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=database.mission-control.apis.io,resources=databases/finalizers,verbs=update
+//+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missions;missionkeys,verbs=get;list;watch
+//+kubebuilder:rbac:groups=pkg.crossplane.io,resources=providers;controllerconfigs,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=sql.gcp.upbound.io,resources=databaseinstances;users,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=rds.aws.upbound.io,resources=instances,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=azure.upbound.io,resources=resourcegroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dbforpostgresql.azure.upbound.io,resources=flexibleservers,verbs=get;list;watch;create;update;patch;delete
+
+func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	database := &databasev1alpha1.Database{}
+	err := r.Get(ctx, req.NamespacedName, database)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !database.GetDeletionTimestamp().IsZero() {
+		return r.DeleteDatabase(ctx, database)
+	}
+	if controllerutil.AddFinalizer(database, utils.Finalizer) {
+		if err := r.Update(ctx, database); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	mission, err := r.GetMission(ctx, database.Spec.MissionRef.MissionName)
+	if err != nil {
+		return ctrl.Result{}, utils.UpdateStatus(ctx, r, database, &database.Status.ConditionedStatus, err)
+	}
+	err = r.ReconcileDatabase(ctx, mission, database)
+	return ctrl.Result{}, utils.UpdateStatus(ctx, r, database, &database.Status.ConditionedStatus, err)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Databases are reconciled again when their Mission or one of its
+	// MissionKeys changes, found through the indexes of clients.SetupIndexes.
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&databasev1alpha1.Database{}).
+		Watches(&missionv1alpha1.Mission{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForMissionRef(&databasev1alpha1.DatabaseList{}))).
+		Watches(&missionv1alpha1.MissionKey{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForMissionRefByKey(&databasev1alpha1.DatabaseList{}))).
+		Owns(&corev1.Secret{})
+	// Provider packages are only watched when the operator installs them,
+	// as their CRD is not required otherwise.
+	if r.Packages != nil {
+		builder = builder.Watches(&cpv1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForPackage(&databasev1alpha1.DatabaseList{})))
+	}
+	// Managed resources are only watched when their CRD is installed, and
+	// once the provider packages installed by the operator add it.
+	r.Managed = clients.NewManagedWatches(mgr, "Database", &databasev1alpha1.Database{}, providers.KindDatabase)
+	return r.Managed.Complete(builder, r)
+}
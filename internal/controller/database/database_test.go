@@ -0,0 +1,281 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	meta "github.com/crossplane/crossplane-runtime/pkg/meta"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awsrdsv1 "github.com/upbound/provider-aws/apis/rds/v1beta1"
+	azuredbv1 "github.com/upbound/provider-azure/apis/dbforpostgresql/v1beta1"
+	gcpsqlv1 "github.com/upbound/provider-gcp/apis/sql/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	types "k8s.io/apimachinery/pkg/types"
+	record "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+func sampleDatabase(name string) *databasev1alpha1.Database {
+	return &databasev1alpha1.Database{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: databasev1alpha1.DatabaseSpec{
+			MissionRef: databasev1alpha1.DatabaseMissionRef{
+				MissionName: "mission-sample",
+			},
+			ForProvider: databasev1alpha1.ProviderData{
+				Name:      name,
+				Region:    "us-east-1",
+				Engine:    "postgres",
+				Version:   "15",
+				Size:      "medium",
+				StorageGB: 64,
+				Username:  "app",
+			},
+			ConnectionSecretRef: xpv1.SecretReference{
+				Name:      name + "-connection",
+				Namespace: "default",
+			},
+		},
+	}
+}
+
+var _ = Describe("Database GCP translation", func() {
+	Context("Converting a stored Database to a Cloud SQL instance", func() {
+		It("Should create a regional instance and its administrator user", func() {
+			ctx := context.Background()
+			database := sampleDatabase("database-sample-gcp")
+			database.Spec.ForProvider.Engine = "mysql"
+			database.Spec.ForProvider.Version = "8.0"
+			database.Spec.ForProvider.HighAvailability = true
+			Expect(k8sClient.Create(ctx, database)).Should(Succeed())
+
+			stored := &databasev1alpha1.Database{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: database.GetName()}, stored)).Should(Succeed())
+			instance, user := stored.Convert2GCP("mission-sample-gcp")
+			Expect(*instance.Spec.ForProvider.DatabaseVersion).To(Equal("MYSQL_8_0"))
+			Expect(*instance.Spec.ForProvider.DeletionProtection).To(BeFalse())
+			settings := instance.Spec.ForProvider.Settings[0]
+			Expect(*settings.Tier).To(Equal("db-custom-2-7680"))
+			Expect(*settings.AvailabilityType).To(Equal("REGIONAL"))
+			Expect(*settings.DiskSize).To(Equal(float64(64)))
+			Expect(*settings.BackupConfiguration[0].BinaryLogEnabled).To(BeTrue())
+			Expect(user.Spec.ForProvider.InstanceRef.Name).To(Equal("database-sample-gcp"))
+			Expect(meta.GetExternalName(user)).To(Equal("app"))
+			Expect(user.Spec.ForProvider.PasswordSecretRef.Name).To(Equal("database-sample-gcp-connection"))
+			Expect(user.Spec.ForProvider.PasswordSecretRef.Key).To(Equal("password"))
+			Expect(user.Spec.ProviderConfigReference.Name).To(Equal("mission-sample-gcp"))
+		})
+	})
+})
+
+var _ = Describe("Database AWS translation", func() {
+	Context("Converting a Database to an RDS instance", func() {
+		It("Should map the size tier and read the password from the connection Secret", func() {
+			database := sampleDatabase("database-sample-aws")
+			database.Spec.ForProvider.HighAvailability = true
+			instance := database.Convert2AWS("mission-sample-aws")
+			Expect(*instance.Spec.ForProvider.Engine).To(Equal("postgres"))
+			Expect(*instance.Spec.ForProvider.EngineVersion).To(Equal("15"))
+			Expect(*instance.Spec.ForProvider.InstanceClass).To(Equal("db.m5.large"))
+			Expect(*instance.Spec.ForProvider.AllocatedStorage).To(Equal(float64(64)))
+			Expect(*instance.Spec.ForProvider.MultiAz).To(BeTrue())
+			Expect(*instance.Spec.ForProvider.Username).To(Equal("app"))
+			Expect(instance.Spec.ForProvider.PasswordSecretRef.Namespace).To(Equal("default"))
+		})
+	})
+})
+
+var _ = Describe("Database Azure translation", func() {
+	Context("Converting a Database to a flexible server", func() {
+		It("Should create a zone redundant server in its resource group", func() {
+			database := sampleDatabase("database-sample-azure")
+			database.Spec.ForProvider.Region = "eastus"
+			database.Spec.ForProvider.HighAvailability = true
+			resourceGroup, server := database.Convert2Azure("mission-sample-azure")
+			Expect(*resourceGroup.Spec.ForProvider.Location).To(Equal("eastus"))
+			Expect(server.Spec.ForProvider.ResourceGroupNameRef.Name).To(Equal("database-database-sample-azure"))
+			Expect(*server.Spec.ForProvider.SkuName).To(Equal("GP_Standard_D2s_v3"))
+			Expect(*server.Spec.ForProvider.StorageMb).To(Equal(float64(65536)))
+			Expect(*server.Spec.ForProvider.HighAvailability[0].Mode).To(Equal("ZoneRedundant"))
+			Expect(*server.Spec.ForProvider.AdministratorLogin).To(Equal("app"))
+		})
+	})
+})
+
+var _ = Describe("Database status", func() {
+	Context("Reconciling a Database", func() {
+		var (
+			ctx = context.Background()
+			c   client.Client
+			r   *DatabaseReconciler
+		)
+		reconcile := func(database *databasev1alpha1.Database) *databasev1alpha1.Database {
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(database)})
+			Expect(err).To(HaveOccurred())
+			stored := &databasev1alpha1.Database{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(database), stored)).To(Succeed())
+			return stored
+		}
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(databasev1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(missionv1alpha1.AddToScheme(scheme)).To(Succeed())
+			c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&databasev1alpha1.Database{}).Build()
+			r = &DatabaseReconciler{
+				MissionClient: clients.MissionClient{Client: c},
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+				APIReader:     c,
+			}
+		})
+
+		It("Should report a failed reconcile through the status subresource", func() {
+			database := sampleDatabase("database-sample-failed")
+			Expect(c.Create(ctx, database)).To(Succeed())
+
+			stored := reconcile(database)
+			Expect(stored.GetFinalizers()).To(ContainElement(utils.Finalizer))
+			synced := stored.Status.GetCondition(xpv1.TypeSynced)
+			Expect(synced.Status).To(Equal(corev1.ConditionFalse))
+			Expect(synced.Reason).To(Equal(xpv1.ReasonReconcileError))
+			Expect(synced.Message).To(ContainSubstring("mission-sample"))
+			ready := stored.Status.GetCondition(xpv1.TypeReady)
+			Expect(ready.Status).To(Equal(corev1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(xpv1.ReasonUnavailable))
+		})
+		It("Should keep the mirrored Ready condition when a later reconcile fails", func() {
+			database := sampleDatabase("database-sample-failed-ready")
+			Expect(c.Create(ctx, database)).To(Succeed())
+			database.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			Expect(c.Status().Update(ctx, database)).To(Succeed())
+
+			stored := reconcile(database)
+			Expect(stored.Status.GetCondition(xpv1.TypeSynced).Reason).To(Equal(xpv1.ReasonReconcileError))
+			Expect(stored.Status.GetCondition(xpv1.TypeReady).Status).To(Equal(corev1.ConditionTrue))
+		})
+	})
+	Context("Observing a database", func() {
+		It("Should store the endpoint through the status subresource and in the connection Secret", func() {
+			ctx := context.Background()
+			database := sampleDatabase("database-sample-status")
+			Expect(k8sClient.Create(ctx, database)).Should(Succeed())
+
+			By("Observing an available instance")
+			instance := &awsrdsv1.Instance{}
+			instance.Status.AtProvider.Address = utils.StrPtr("database-sample-status.rds.amazonaws.com")
+			port := float64(5433)
+			instance.Status.AtProvider.Port = &port
+			instance.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			database.Status.Provider = "aws"
+			database.ObserveAWS(instance)
+			Expect(k8sClient.Status().Update(ctx, database)).Should(Succeed())
+
+			stored := &databasev1alpha1.Database{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: database.GetName()}, stored)).Should(Succeed())
+			Expect(stored.Status.Endpoint).To(Equal("database-sample-status.rds.amazonaws.com"))
+			Expect(stored.Status.Port).To(Equal(5433))
+			Expect(stored.Status.GetCondition(xpv1.TypeReady).Status).To(Equal(corev1.ConditionTrue))
+
+			secret := stored.ConnectionSecret("secret")
+			Expect(secret.GetNamespace()).To(Equal("default"))
+			Expect(secret.Data).To(Equal(map[string][]byte{
+				"username": []byte("app"),
+				"password": []byte("secret"),
+				"endpoint": []byte("database-sample-status.rds.amazonaws.com"),
+				"port":     []byte("5433"),
+			}))
+		})
+		It("Should not be ready while the administrator user is still being created", func() {
+			database := sampleDatabase("database-sample-creating")
+			instance := &gcpsqlv1.DatabaseInstance{}
+			instance.Status.SetConditions(xpv1.Available())
+			database.ObserveGCP(instance, &gcpsqlv1.User{})
+			Expect(database.Status.GetCondition(xpv1.TypeReady).Reason).To(Equal(xpv1.ReasonCreating))
+			Expect(database.Status.Port).To(Equal(5432))
+
+			azure := sampleDatabase("database-sample-azure-creating")
+			azure.ObserveAzure(&azuredbv1.FlexibleServer{})
+			Expect(azure.Status.GetCondition(xpv1.TypeReady).Reason).To(Equal(xpv1.ReasonCreating))
+			Expect(azure.ConnectionSecret("secret").Data).NotTo(HaveKey("endpoint"))
+		})
+	})
+})
+
+var _ = Describe("Database password", func() {
+	Context("Reading the administrator password", func() {
+		var (
+			ctx      = context.Background()
+			scheme   *runtime.Scheme
+			database *databasev1alpha1.Database
+		)
+		reconciler := func(cached client.Client, objects ...client.Object) *DatabaseReconciler {
+			return &DatabaseReconciler{
+				MissionClient: clients.MissionClient{Client: cached},
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+				APIReader:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			}
+		}
+		connectionSecret := func(password string) *corev1.Secret {
+			secret := database.ConnectionSecret(password)
+			Expect(controllerutil.SetControllerReference(database, secret, scheme)).To(Succeed())
+			return secret
+		}
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(databasev1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			database = sampleDatabase("database-sample-password")
+			database.UID = "database-sample-password"
+		})
+
+		It("Should generate a password only when there is no Secret", func() {
+			password, err := reconciler(fake.NewClientBuilder().WithScheme(scheme).Build()).GetPassword(ctx, database)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(password).To(HaveLen(passwordLength))
+		})
+		It("Should keep the password of a Secret the cache has not seen yet", func() {
+			r := reconciler(fake.NewClientBuilder().WithScheme(scheme).Build(), connectionSecret("secret"))
+			Expect(r.GetPassword(ctx, database)).To(Equal("secret"))
+		})
+		It("Should refuse Secrets without a password or owned by someone else", func() {
+			empty := connectionSecret("")
+			empty.Data = nil
+			_, err := reconciler(fake.NewClientBuilder().WithScheme(scheme).Build(), empty).GetPassword(ctx, database)
+			Expect(err).To(HaveOccurred())
+
+			other := database.ConnectionSecret("secret")
+			_, err = reconciler(fake.NewClientBuilder().WithScheme(scheme).Build(), other).GetPassword(ctx, database)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,136 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// KeyProvider holds the key that decrypts the data encryption keys of
+// MissionKeys. Data is encrypted with a random data key, and only that data
+// key is encrypted by the provider, so providers backed by a KMS never see
+// the credentials themselves.
+type KeyProvider interface {
+	// Name is the identifier used in the encryption of MissionKeys.
+	Name() string
+	// DecryptKey returns the data key encrypted for this provider.
+	DecryptKey(ctx context.Context, encryptedKey []byte) ([]byte, error)
+}
+
+// registry holds the key providers by name. Providers are registered at
+// startup, but looked up by the controller and webhooks concurrently.
+var registry = struct {
+	sync.RWMutex
+	providers map[string]KeyProvider
+}{providers: map[string]KeyProvider{}}
+
+// Register makes a key provider available to the MissionKey controller.
+func Register(p KeyProvider) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.providers[p.Name()] = p
+}
+
+func Get(name string) (KeyProvider, error) {
+	registry.RLock()
+	p, ok := registry.providers[name]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Key provider %s not configured, please use one of %v", name, Names())
+	}
+	return p, nil
+}
+
+// Names returns the names of the registered key providers, sorted.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	var names []string
+	for name := range registry.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AdditionalData binds sealed data to the MissionKey it belongs to, so data
+// copied into another key, or a key of another type, does not open.
+func AdditionalData(namespace, name, keyType string) []byte {
+	return []byte(namespace + "/" + name + "/" + keyType)
+}
+
+// Seal encrypts data with a new AES-256-GCM data key, and encrypts that key
+// for publicKey with RSA-OAEP. Sealed data is the nonce followed by the
+// ciphertext, authenticated together with additionalData.
+func Seal(publicKey *rsa.PublicKey, data, additionalData []byte) (encryptedKey, sealed []byte, err error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+	encryptedKey, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, dataKey, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return encryptedKey, aead.Seal(nonce, nonce, data, additionalData), nil
+}
+
+// Open decrypts data sealed by Seal with the same additionalData, using
+// provider to decrypt the data key.
+func Open(ctx context.Context, provider KeyProvider, encryptedKey, sealed, additionalData []byte) ([]byte, error) {
+	dataKey, err := provider.DecryptKey(ctx, encryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("Could not decrypt data key with %s: %w", provider.Name(), err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("Encrypted data is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.New("Could not decrypt data, it does not match its data key or MissionKey")
+	}
+	return data, nil
+}
+
+func newAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
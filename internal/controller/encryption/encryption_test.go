@@ -0,0 +1,135 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newLocal(t *testing.T) *Local {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	local, err := NewLocal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return local
+}
+
+func TestSealOpen(t *testing.T) {
+	local := newLocal(t)
+	data := []byte(`{"type": "service_account"}`)
+	encryptedKey, sealed, err := Seal(local.PublicKey(), data, AdditionalData("default", "gcp-key", "gcp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, data) {
+		t.Error("sealed data contains the plaintext")
+	}
+	opened, err := Open(context.Background(), local, encryptedKey, sealed, AdditionalData("default", "gcp-key", "gcp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, data) {
+		t.Errorf("expected %s, got %s", data, opened)
+	}
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	local := newLocal(t)
+	additionalData := AdditionalData("default", "gcp-key", "gcp")
+	encryptedKey, sealed, err := Seal(local.PublicKey(), []byte("secret"), additionalData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := Open(context.Background(), local, encryptedKey, tampered, additionalData); err == nil {
+		t.Error("expected tampered data to fail")
+	}
+	if _, err := Open(context.Background(), newLocal(t), encryptedKey, sealed, additionalData); err == nil {
+		t.Error("expected another private key to fail")
+	}
+	if _, err := Open(context.Background(), local, encryptedKey, sealed[:4], additionalData); err == nil {
+		t.Error("expected short data to fail")
+	}
+}
+
+func TestOpenRejectsOtherKeys(t *testing.T) {
+	local := newLocal(t)
+	encryptedKey, sealed, err := Seal(local.PublicKey(), []byte("secret"), AdditionalData("default", "gcp-key", "gcp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, additionalData := range [][]byte{
+		AdditionalData("team-a", "gcp-key", "gcp"),
+		AdditionalData("default", "other-key", "gcp"),
+		AdditionalData("default", "gcp-key", "aws"),
+	} {
+		if _, err := Open(context.Background(), local, encryptedKey, sealed, additionalData); err == nil {
+			t.Errorf("expected data moved to %s to fail", additionalData)
+		}
+	}
+}
+
+func TestNewLocalRejectsInvalidFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLocal(path); err == nil {
+		t.Error("expected an error for a file without PEM data")
+	}
+	if _, err := NewLocal(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if _, err := Get(LocalName); err == nil {
+		t.Fatal("expected no provider before registering")
+	}
+	local := newLocal(t)
+	Register(local)
+	defer func() {
+		registry.Lock()
+		delete(registry.providers, LocalName)
+		registry.Unlock()
+	}()
+	p, err := Get(LocalName)
+	if err != nil || p != local {
+		t.Errorf("expected the registered provider, got %v %v", p, err)
+	}
+}
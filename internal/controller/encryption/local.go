@@ -0,0 +1,85 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// LocalName is the name of the key provider reading an RSA key from a file.
+const LocalName = "local"
+
+// Local decrypts data keys with an RSA private key read from a file, such as
+// a Secret mounted into the operator.
+type Local struct {
+	privateKey *rsa.PrivateKey
+}
+
+var _ KeyProvider = &Local{}
+
+// NewLocal reads a PEM encoded PKCS#1 or PKCS#8 RSA private key.
+func NewLocal(path string) (*Local, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	privateKey, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &Local{privateKey: privateKey}, nil
+}
+
+func (l *Local) Name() string {
+	return LocalName
+}
+
+func (l *Local) DecryptKey(ctx context.Context, encryptedKey []byte) ([]byte, error) {
+	return rsa.DecryptOAEP(sha256.New(), rand.Reader, l.privateKey, encryptedKey, nil)
+}
+
+// PublicKey is the key MissionKey data has to be sealed with.
+func (l *Local) PublicKey() *rsa.PublicKey {
+	return &l.privateKey.PublicKey
+}
+
+func parsePrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.New("not an RSA private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA private key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package missioncontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// MissionDependents lists the resources that still reference the Mission
+// through their missionRef, found through the MissionRefField index.
+func MissionDependents(ctx context.Context, r *MissionReconciler, mission *missionv1alpha1.Mission) ([]string, error) {
+	var dependents []string
+	lists := map[string]client.ObjectList{
+		"VirtualMachine": &computev1alpha1.VirtualMachineList{},
+		"StorageBuckets": &storagev1alpha1.StorageBucketsList{},
+		"Network":        &networkv1alpha1.NetworkList{},
+		"Database":       &databasev1alpha1.DatabaseList{},
+	}
+	for kind, list := range lists {
+		if err := r.List(ctx, list, client.MatchingFields{clients.MissionRefField: mission.Name}); err != nil {
+			return nil, err
+		}
+		err := meta.EachListItem(list, func(object runtime.Object) error {
+			dependents = append(dependents, kind+"/"+object.(client.Object).GetName())
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(dependents)
+	return dependents, nil
+}
+
+// DeleteMission keeps the finalizer on the Mission for as long as resources
+// reference it, so their ProviderConfigs outlive them.
+func (r *MissionReconciler) DeleteMission(ctx context.Context, mission *missionv1alpha1.Mission) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(mission, utils.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+	dependents, err := MissionDependents(ctx, r, mission)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(dependents) > 0 {
+		message := fmt.Sprintf("Mission is still referenced by %s", strings.Join(dependents, ", "))
+		r.Recorder.Event(mission, "Warning", "Deleting", message)
+		mission.Status.SetConditions(xpv1.Deleting().WithMessage(message))
+		if err := r.Status().Update(ctx, mission); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	controllerutil.RemoveFinalizer(mission, utils.Finalizer)
+	return ctrl.Result{}, r.Update(ctx, mission)
+}
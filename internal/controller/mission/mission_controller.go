@@ -20,20 +20,35 @@ import (
 	"context"
 	"errors"
 
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
 	record "k8s.io/client-go/tools/record"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	builder "sigs.k8s.io/controller-runtime/pkg/builder"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	event "sigs.k8s.io/controller-runtime/pkg/event"
+	handler "sigs.k8s.io/controller-runtime/pkg/handler"
+	predicate "sigs.k8s.io/controller-runtime/pkg/predicate"
+	reconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	awsv1 "github.com/upbound/provider-aws/apis/v1beta1"
-	azrv1 "github.com/upbound/provider-azure/apis/v1beta1"
-	gcpv1 "github.com/upbound/provider-gcp/apis/v1beta1"
-
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
 	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
 	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
 	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
 )
 
+// crossplaneProvider is the kind installed with Crossplane that providers are
+// packaged as.
+var crossplaneProvider = schema.GroupVersionKind{Group: "pkg.crossplane.io", Version: "v1", Kind: "Provider"}
+
 type MissionReconciler struct {
 	clients.MissionClient
 	Scheme   *runtime.Scheme
@@ -43,42 +58,91 @@ type MissionReconciler struct {
 //+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missions/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missions/finalizers,verbs=update
+//+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missionkeys,verbs=get;list;watch
+//+kubebuilder:rbac:groups=compute.mission-control.apis.io,resources=virtualmachines,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.mission-control.apis.io,resources=storagebuckets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=network.mission-control.apis.io,resources=networks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=database.mission-control.apis.io,resources=databases,verbs=get;list;watch
+//+kubebuilder:rbac:groups=pkg.crossplane.io,resources=providers;controllerconfigs,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=gcp.upbound.io;aws.upbound.io;azure.upbound.io,resources=providerconfigs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *MissionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	mission := &missionv1alpha1.Mission{}
 	if err := r.Get(ctx, req.NamespacedName, mission); err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !mission.GetDeletionTimestamp().IsZero() {
+		return r.DeleteMission(ctx, mission)
 	}
+	if controllerutil.AddFinalizer(mission, utils.Finalizer) {
+		if err := r.Update(ctx, mission); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	ResetPackageStatus(mission)
 	// Ensure crossplane is installed in the kubernetes cluster
-	if err := utils.ConfirmCRD(ctx, "providers.pkg.crossplane.io"); err != nil {
+	if err := utils.ConfirmCRD(r.RESTMapper(), crossplaneProvider); err != nil {
 		r.Recorder.Event(mission, "Warning", "Failed", "Crossplane installation not found")
-		return ctrl.Result{}, errors.New("could not find crossplane CRD \"Provider\"")
+		return ctrl.Result{}, r.UpdateMissionStatus(ctx, mission, errors.New("could not find crossplane CRD \"Provider\""))
+	}
+	// Install the provider families of the Mission when enabled.
+	if err := InstallProviderPackages(ctx, r, mission); err != nil {
+		r.Recorder.Event(mission, "Warning", "Provider package not ready", err.Error())
+		return ctrl.Result{}, r.UpdateMissionStatus(ctx, mission, err)
 	}
 	// Ensure crossplane providers are installed in the kubernetes cluster
-	if err := ConfirmProviderConfigs(ctx, mission); err != nil {
+	if err := ConfirmProviderConfigs(ctx, r, mission); err != nil {
 		r.Recorder.Event(mission, "Warning", "Failed", err.Error())
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.UpdateMissionStatus(ctx, mission, err)
 	}
 	r.Recorder.Event(mission, "Normal", "Success", "Mission correctly connected to Crossplane")
 	// Create ProviderConfigs that resources will reference.
 	if err := ReconcileProviderConfigs(ctx, r, mission); err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.UpdateMissionStatus(ctx, mission, err)
 	}
 	r.Recorder.Event(mission, "Normal", "Success", "ProviderConfig correctly created")
 	// Warn if mission keys are not created.
 	if err := ConfirmMissionKeys(ctx, r, mission); err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.UpdateMissionStatus(ctx, mission, err)
 	}
 	r.Recorder.Event(mission, "Normal", "Success", "Mission keys correctly synced")
-	return ctrl.Result{}, nil
+	return ctrl.Result{}, r.UpdateMissionStatus(ctx, mission, nil)
+}
+
+// requestForMissionRef maps a VirtualMachine, StorageBuckets, Network or
+// Database to its Mission.
+func requestForMissionRef(ctx context.Context, object client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, name := range clients.IndexMissionRef(object) {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+	return requests
 }
 
 func (r *MissionReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// Missions are reconciled again when one of their MissionKeys changes,
+	// and when a resource referencing them is deleted so that their own
+	// deletion does not wait for the next poll.
+	deleted := builder.WithPredicates(predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	})
+	controller := ctrl.NewControllerManagedBy(mgr).
 		For(&missionv1alpha1.Mission{}).
-		Owns(&gcpv1.ProviderConfig{}).
-		Owns(&awsv1.ProviderConfig{}).
-		Owns(&azrv1.ProviderConfig{}).
-		Complete(r)
+		Watches(&missionv1alpha1.MissionKey{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForKey)).
+		Watches(&computev1alpha1.VirtualMachine{}, handler.EnqueueRequestsFromMapFunc(requestForMissionRef), deleted).
+		Watches(&storagev1alpha1.StorageBuckets{}, handler.EnqueueRequestsFromMapFunc(requestForMissionRef), deleted).
+		Watches(&networkv1alpha1.Network{}, handler.EnqueueRequestsFromMapFunc(requestForMissionRef), deleted).
+		Watches(&databasev1alpha1.Database{}, handler.EnqueueRequestsFromMapFunc(requestForMissionRef), deleted)
+	// Provider packages are only watched when the operator installs them,
+	// as their CRD is not required otherwise.
+	if r.Packages != nil {
+		controller = controller.Watches(&cpv1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForPackage(&missionv1alpha1.MissionList{})))
+	}
+	// Managed resources are only watched when their CRD is installed, and
+	// once the provider packages installed by the operator add it.
+	r.Managed = clients.NewManagedWatches(mgr, "Mission", &missionv1alpha1.Mission{}, providers.KindProviderConfig)
+	return r.Managed.Complete(controller, r)
 }
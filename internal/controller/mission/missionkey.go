@@ -22,21 +22,45 @@ import (
 
 	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	types "k8s.io/apimachinery/pkg/types"
 )
 
 func ConfirmMissionKeys(ctx context.Context, r *MissionReconciler, mission *missionv1alpha1.Mission) error {
-	for _, pkg := range mission.Spec.Packages {
+	for i := range mission.Spec.Packages {
+		pkg := &mission.Spec.Packages[i]
 		key := &missionv1alpha1.MissionKey{}
-		err := r.Get(ctx, types.NamespacedName{Name: pkg.Credentials.Name, Namespace: pkg.Credentials.Namespace}, key)
+		err := r.Get(ctx, pkg.KeyRef(), key)
 		if err != nil {
 			if !k8serrors.IsNotFound(err) {
 				r.Recorder.Event(mission, "Warning", "Error looking for MissionKey", "Unexpected error while looking for MissionKey.")
 				return err
 			}
-			message := fmt.Sprintf("Provider %s: Please ensure that MissionKey \"%s\" exists in namespace \"%s\".", pkg.Provider, pkg.Credentials.Name, pkg.Credentials.Namespace)
+			message := fmt.Sprintf("Provider %s: Please ensure that MissionKey \"%s\" exists in namespace \"%s\".", pkg.Provider, pkg.KeyRef().Name, pkg.KeyRef().Namespace)
 			r.Recorder.Event(mission, "Warning", "MissionKey not found", message)
+			continue
 		}
+		if !key.AllowsMission(mission.GetName()) {
+			message := fmt.Sprintf("Provider %s: MissionKey %s does not list this Mission in allowedMissions.", pkg.Provider, pkg.KeyRef())
+			r.Recorder.Event(mission, "Warning", "MissionKey not allowed", message)
+			continue
+		}
+		mission.Status.Packages[i].MissionKeyFound = true
 	}
 	return nil
 }
+
+// PackageKey returns the MissionKey a package reads its credentials from.
+// The ProviderConfig of a package grants access to whatever the key points
+// at, so it is only created once the key exists and allows the Mission.
+func PackageKey(ctx context.Context, r *MissionReconciler, mission *missionv1alpha1.Mission, pkg *missionv1alpha1.PackageConfig) (*missionv1alpha1.MissionKey, error) {
+	key := &missionv1alpha1.MissionKey{}
+	if err := r.Get(ctx, pkg.KeyRef(), key); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("MissionKey %s not found", pkg.KeyRef())
+		}
+		return nil, err
+	}
+	if !key.AllowsMission(mission.GetName()) {
+		return nil, fmt.Errorf("MissionKey %s does not allow Mission %s", pkg.KeyRef(), mission.GetName())
+	}
+	return key, nil
+}
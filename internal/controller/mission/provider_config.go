@@ -21,11 +21,11 @@ import (
 	"errors"
 	"fmt"
 
+	apiutil "sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
 	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
 	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
-	awsv1 "github.com/upbound/provider-aws/apis/v1beta1"
-	azrv1 "github.com/upbound/provider-azure/apis/v1beta1"
-	gcpv1 "github.com/upbound/provider-gcp/apis/v1beta1"
 )
 
 func ReconcileProviderConfigs(ctx context.Context, r *MissionReconciler, mission *missionv1alpha1.Mission) error {
@@ -36,64 +36,65 @@ func ReconcileProviderConfigs(ctx context.Context, r *MissionReconciler, mission
 			r.Recorder.Event(mission, "Warning", "ProviderConfig not created", message)
 			return err
 		}
+		mission.Status.Packages[i].ProviderConfigCreated = true
 	}
 	return nil
 }
 
 func ReconcileProviderConfigByProvider(ctx context.Context, r *MissionReconciler, mission *missionv1alpha1.Mission, packageId int) error {
-	var err error
 	pkg := &mission.Spec.Packages[packageId]
-	if pkg.Provider == "gcp" {
-		err = mission.GCPVerify(packageId)
-		if err != nil {
-			return err
-		}
-		err = r.ReconcileObject(
-			ctx,
-			mission,
-			&gcpv1.ProviderConfig{},
-			mission.Convert2GCP(pkg),
-		)
-	} else if pkg.Provider == "aws" {
-		err = mission.AWSVerify()
-		if err != nil {
-			return err
-		}
-		err = r.ReconcileObject(
-			ctx,
-			mission,
-			&awsv1.ProviderConfig{},
-			mission.Convert2AWS(pkg),
-		)
-	} else if pkg.Provider == "azure" {
-		err = mission.AzureVerify()
-		if err != nil {
-			return err
-		}
-		err = r.ReconcileObject(
-			ctx,
-			mission,
-			&azrv1.ProviderConfig{},
-			mission.Convert2Azure(pkg),
-		)
-	} else {
-		message := fmt.Sprintf("Provider %s not known", pkg.Provider)
-		err = errors.New(message)
+	provider, err := providers.Get(pkg.Provider)
+	if err != nil {
+		return err
+	}
+	if err := provider.VerifyMission(mission, packageId); err != nil {
+		return err
 	}
+	key, err := PackageKey(ctx, r, mission, pkg)
 	if err != nil {
 		return err
 	}
-	return nil
+	providerConfig := provider.ProviderConfig(mission, pkg, key)
+	return r.ApplyObject(ctx, mission, providerConfig)
+}
+
+// InstallProviderPackages installs the provider family of every package of
+// the Mission when the operator installs provider packages, and fails until
+// they are healthy.
+func InstallProviderPackages(ctx context.Context, r *MissionReconciler, mission *missionv1alpha1.Mission) error {
+	var errs []error
+	for _, pkg := range mission.Spec.Packages {
+		provider, err := providers.Get(pkg.Provider)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, r.InstallPackages(ctx, provider, provider.Package()))
+	}
+	return errors.Join(errs...)
 }
 
-func ConfirmProviderConfigs(ctx context.Context, mission *missionv1alpha1.Mission) error {
+func ConfirmProviderConfigs(ctx context.Context, r *MissionReconciler, mission *missionv1alpha1.Mission) error {
 	// Check that all providers being used in specified mission
 	// are installed in the cluster and are supported.
-	for _, p := range mission.Spec.Packages {
-		providerCRD := fmt.Sprintf("providerconfigs.%s.upbound.io", p.Provider)
-		if err := utils.ConfirmCRD(ctx, providerCRD); err != nil {
-			return err
+	var errs []error
+	for i := range mission.Spec.Packages {
+		pkg := &mission.Spec.Packages[i]
+		provider, err := providers.Get(pkg.Provider)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		gvk, err := apiutil.GVKForObject(provider.ProviderConfig(mission, pkg, nil), r.Scheme)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := utils.ConfirmCRD(r.RESTMapper(), gvk); err != nil {
+			errs = append(errs, err)
+			continue
 		}
+		mission.Status.Packages[i].CRDFound = true
 	}
-	return nil
+	return errors.Join(errs...)
 }
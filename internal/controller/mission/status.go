@@ -0,0 +1,76 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package missioncontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+)
+
+// ResetPackageStatus starts a fresh per-package status that is filled in as
+// each reconciling step succeeds.
+func ResetPackageStatus(mission *missionv1alpha1.Mission) {
+	mission.Status.Packages = make([]missionv1alpha1.PackageStatus, len(mission.Spec.Packages))
+	for i, pkg := range mission.Spec.Packages {
+		mission.Status.Packages[i].Provider = pkg.Provider
+	}
+}
+
+// UnreadyPackages describes every package that is not yet usable, or returns
+// an empty string if all of them are.
+func UnreadyPackages(mission *missionv1alpha1.Mission) string {
+	var problems []string
+	for _, pkg := range mission.Status.Packages {
+		if !pkg.CRDFound {
+			problems = append(problems, fmt.Sprintf("%s: provider CRD not found", pkg.Provider))
+		}
+		if !pkg.ProviderConfigCreated {
+			problems = append(problems, fmt.Sprintf("%s: ProviderConfig not created", pkg.Provider))
+		}
+		if !pkg.MissionKeyFound {
+			problems = append(problems, fmt.Sprintf("%s: MissionKey not found", pkg.Provider))
+		}
+	}
+	return strings.Join(problems, "; ")
+}
+
+// UpdateMissionStatus writes conditions and package state through the status
+// subresource. The reconcile error is returned so callers can pass it through.
+func (r *MissionReconciler) UpdateMissionStatus(ctx context.Context, mission *missionv1alpha1.Mission, reconcileErr error) error {
+	mission.Status.ObservedGeneration = mission.GetGeneration()
+	if reconcileErr != nil {
+		mission.Status.SetConditions(xpv1.ReconcileError(reconcileErr))
+	} else {
+		mission.Status.SetConditions(xpv1.ReconcileSuccess())
+	}
+	if message := UnreadyPackages(mission); message != "" {
+		mission.Status.SetConditions(xpv1.Unavailable().WithMessage(message))
+	} else if reconcileErr != nil {
+		mission.Status.SetConditions(xpv1.Unavailable())
+	} else {
+		mission.Status.SetConditions(xpv1.Available())
+	}
+	if err := r.Status().Update(ctx, mission); err != nil && reconcileErr == nil {
+		return err
+	}
+	return reconcileErr
+}
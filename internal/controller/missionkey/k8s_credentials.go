@@ -18,18 +18,119 @@ package missionkeycontroller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
 
 	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	encryption "github.com/holy-tech/Mission-Control-Operator/internal/controller/encryption"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
 )
 
-func (r *MissionKeyReconciler) ReconcileSecret(ctx context.Context, key *missionv1alpha1.MissionKey) error {
-	secret := key.Convert2Secret()
-	return r.ReconcileObject(ctx, key, &v1.Secret{}, secret, "Data")
+// ResolveKey returns the key with its credentials inline and in clear: read
+// from the referenced Secret of a Secret source, or decrypted with a key
+// provider of the operator. Other keys are returned as they are. Keys with
+// a Secret source take their fingerprint from the Secret.
+func (r *MissionKeyReconciler) ResolveKey(ctx context.Context, key *missionv1alpha1.MissionKey) (*missionv1alpha1.MissionKey, error) {
+	if key.Spec.Encryption == nil && key.Spec.Source != xpv1.CredentialsSourceSecret {
+		return key, nil
+	}
+	// Refuse references outside the namespace before reading anything.
+	if err := key.GenericVerify(); err != nil {
+		return nil, err
+	}
+	var data []byte
+	if key.Spec.Encryption != nil {
+		provider, err := encryption.Get(key.Spec.Encryption.Provider)
+		if err != nil {
+			return nil, err
+		}
+		// Keys are sealed for their normalized type, which the defaulting
+		// webhook may not have written back.
+		additionalData := encryption.AdditionalData(key.GetNamespace(), key.GetName(), utils.NormalizeProvider(key.Spec.Type))
+		data, err = encryption.Open(ctx, provider, key.Spec.Encryption.EncryptedKey, key.Spec.Data, additionalData)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ref := key.Spec.SecretRef
+		secret := &v1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret); err != nil {
+			return nil, fmt.Errorf("Could not read Secret %s: %w", ref.Name, err)
+		}
+		var ok bool
+		if data, ok = secret.Data[ref.Key]; !ok {
+			return nil, fmt.Errorf("Secret %s has no key %s.", ref.Name, ref.Key)
+		}
+		key.Status.Fingerprint = missionv1alpha1.SecretFingerprint(secret)
+	}
+	return key.WithData(data), nil
 }
 
+// VerifyKey checks resolved credentials with the provider of the key.
+func VerifyKey(resolved *missionv1alpha1.MissionKey) error {
+	provider, err := providers.Get(resolved.Spec.Type)
+	if err != nil {
+		return err
+	}
+	return provider.VerifyKey(resolved)
+}
+
+// ReconcileSecret keeps the resolved key data in a Secret of the same name,
+// resolved is nil when the data could not be read. Keys whose credentials
+// live elsewhere have no data, so a Secret left over from earlier is removed
+// instead.
+func (r *MissionKeyReconciler) ReconcileSecret(ctx context.Context, key, resolved *missionv1alpha1.MissionKey) error {
+	if !key.StoresData() {
+		return r.DeleteOwnedSecret(ctx, key, key.Convert2Secret())
+	}
+	if resolved == nil {
+		return errors.New("Key data could not be read, the Secret is left as it is.")
+	}
+	return r.ApplyObject(ctx, key, resolved.Convert2Secret())
+}
+
+// DeleteOwnedSecret deletes the named Secret only if the key controls it, so
+// Secrets the key merely references are never touched.
+func (r *MissionKeyReconciler) DeleteOwnedSecret(ctx context.Context, key *missionv1alpha1.MissionKey, secret *v1.Secret) error {
+	current := &v1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: secret.GetName(), Namespace: secret.GetNamespace()}, current)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(current, key) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, current))
+}
+
+// ReconcileServiceAccount keeps the ServiceAccount of the key and its
+// identity annotations up to date. Annotations set by others are left alone,
+// and identity annotations the key no longer sets are dropped by the apply.
 func (r *MissionKeyReconciler) ReconcileServiceAccount(ctx context.Context, key *missionv1alpha1.MissionKey) error {
-	serviceAccount := key.Convert2ServiceAccount()
-	return r.ReconcileObject(ctx, key, &v1.Secret{}, serviceAccount, "Data")
+	return r.ApplyObject(ctx, key, key.Convert2ServiceAccount())
+}
+
+// ReconcileIdentity runs the provider packages installed by the operator with
+// the workload identity of the key, so that its ProviderConfigs using the
+// injected identity authenticate.
+func (r *MissionKeyReconciler) ReconcileIdentity(ctx context.Context, key *missionv1alpha1.MissionKey) error {
+	if !key.UsesIdentity() {
+		return nil
+	}
+	provider, err := providers.Get(key.Spec.Type)
+	if err != nil {
+		return err
+	}
+	return r.InstallIdentity(ctx, provider, key)
 }
@@ -0,0 +1,76 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package missionkeycontroller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	encryption "github.com/holy-tech/Mission-Control-Operator/internal/controller/encryption"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+func TestResolveKeyNormalizesType(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	local, err := encryption.NewLocal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryption.Register(local)
+
+	// cmd/missionkey-encrypt seals keys for their normalized type.
+	data := []byte(`{"type": "service_account"}`)
+	encryptedKey, sealed, err := encryption.Seal(local.PublicKey(), data, encryption.AdditionalData("default", "gcp-key", utils.NormalizeProvider("GCP")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &missionv1alpha1.MissionKey{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcp-key", Namespace: "default"},
+		Spec: missionv1alpha1.MissionKeySpec{
+			Type:       "GCP",
+			Data:       sealed,
+			Encryption: &missionv1alpha1.DataEncryption{Provider: local.Name(), EncryptedKey: encryptedKey},
+		},
+	}
+	resolved, err := newReconciler(t).ResolveKey(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resolved.Spec.Data) != string(data) {
+		t.Errorf("expected %s, got %s", data, resolved.Spec.Data)
+	}
+}
@@ -18,12 +18,18 @@ package missionkeycontroller
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	record "k8s.io/client-go/tools/record"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	handler "sigs.k8s.io/controller-runtime/pkg/handler"
+	reconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
 	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
@@ -38,33 +44,107 @@ type MissionKeyReconciler struct {
 //+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missionkeys,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missionkeys/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missionkeys/finalizers,verbs=update
+//+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missions,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=pkg.crossplane.io,resources=controllerconfigs,verbs=get;list;watch;create;update;patch
 
 func (r *MissionKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := &missionv1alpha1.MissionKey{}
 	err := r.Get(ctx, req.NamespacedName, key)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	// Ensure MissionKey is correct
-	if err := key.GenericVerify(); err != nil {
+	key.Status.Fingerprint = key.Fingerprint()
+	// Ensure MissionKey is correct, reading credentials kept elsewhere
+	resolved, err := r.ResolveKey(ctx, key)
+	if err == nil {
+		err = VerifyKey(resolved)
+	}
+	if err != nil {
 		r.Recorder.Event(key, "Warning", "Failed", err.Error())
 	}
-	// Reconcile provider credential secrets
-	if err := r.ReconcileSecret(ctx, key); err != nil {
-		return ctrl.Result{}, err
+	key.Status.SetConditions(missionv1alpha1.KeyCondition(missionv1alpha1.TypeCredentialsValid, err))
+	// List the missions using this key
+	if err := r.ReconcileKeyMissions(ctx, key); err != nil {
+		return ctrl.Result{}, r.UpdateMissionKeyStatus(ctx, key, err)
+	}
+	// Track expiry of the credentials
+	now := time.Now()
+	expiry := key.ExpiryCondition(now)
+	if expiry.Reason == "Expiring" || expiry.Reason == "Expired" {
+		r.Recorder.Event(key, "Warning", string(expiry.Reason), expiry.Message)
+	}
+	key.Status.SetConditions(expiry)
+	// Reconcile provider credential secrets, keeping replaced credentials
+	// during the overlap window
+	overlap, err := r.ExpirePreviousSecret(ctx, key, now)
+	if err == nil && key.Expired(now) {
+		// Only new credentials fix this, so it is not a reconcile error.
+		expired := errors.New("Credentials expired, the Secret is no longer synced.")
+		key.Status.SetConditions(missionv1alpha1.KeyCondition(missionv1alpha1.TypeSecretSynced, expired))
+	} else {
+		if err == nil {
+			err = r.KeepPreviousSecret(ctx, key, resolved)
+		}
+		if err == nil {
+			err = r.ReconcileSecret(ctx, key, resolved)
+		}
+		key.Status.SetConditions(missionv1alpha1.KeyCondition(missionv1alpha1.TypeSecretSynced, err))
+		if err != nil {
+			return ctrl.Result{}, r.UpdateMissionKeyStatus(ctx, key, err)
+		}
+	}
+	// Reconcile service account for key usage, and the identity provider
+	// packages run with
+	err = r.ReconcileServiceAccount(ctx, key)
+	if err == nil {
+		err = r.ReconcileIdentity(ctx, key)
+	}
+	key.Status.SetConditions(missionv1alpha1.KeyCondition(missionv1alpha1.TypeServiceAccountSynced, err))
+	if key.Status.RotatedAt != nil && overlap == 0 {
+		overlap = key.OverlapWindow()
 	}
-	// Reconcile service account for key usage
-	if err := r.ReconcileServiceAccount(ctx, key); err != nil {
-		return ctrl.Result{}, err
+	return ctrl.Result{RequeueAfter: RequeueAfter(key, now, overlap)}, r.UpdateMissionKeyStatus(ctx, key, err)
+}
+
+// ReconcileKeyMissions records which Missions read their credentials from
+// the key and are allowed to. Missions are found through the credentials
+// index of clients.SetupIndexes.
+func (r *MissionKeyReconciler) ReconcileKeyMissions(ctx context.Context, key *missionv1alpha1.MissionKey) error {
+	missions, err := r.MissionsForKey(ctx, key)
+	if err != nil {
+		return err
 	}
+	key.Status.Missions = nil
+	for _, mission := range missions {
+		if key.AllowsMission(mission.GetName()) {
+			key.Status.Missions = append(key.Status.Missions, mission.GetName())
+		}
+	}
+	sort.Strings(key.Status.Missions)
+	return nil
+}
 
-	return ctrl.Result{}, nil
+// requestsForMission maps a Mission to the MissionKeys of its packages, so
+// their list of Missions stays current.
+func requestsForMission(ctx context.Context, object client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, pkg := range object.(*missionv1alpha1.Mission).Spec.Packages {
+		requests = append(requests, reconcile.Request{NamespacedName: pkg.KeyRef()})
+	}
+	return requests
 }
 
 func (r *MissionKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Keys with a Secret source are reconciled again when that Secret
+	// changes, found through the index of clients.SetupIndexes.
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&missionv1alpha1.MissionKey{}).
 		Owns(&v1.Secret{}).
 		Owns(&v1.ServiceAccount{}).
+		Watches(&missionv1alpha1.Mission{}, handler.EnqueueRequestsFromMapFunc(requestsForMission)).
+		Watches(&v1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForSecret)).
 		Complete(r)
 }
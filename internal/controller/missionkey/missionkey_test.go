@@ -0,0 +1,160 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package missionkeycontroller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	record "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+)
+
+const teamKey = `[default]
+aws_access_key_id = AKIAEXAMPLE
+aws_secret_access_key = secret
+`
+
+func newTeamMission(name, keyNamespace string) *missionv1alpha1.Mission {
+	return &missionv1alpha1.Mission{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: missionv1alpha1.MissionSpec{
+			Packages: []missionv1alpha1.PackageConfig{{
+				Provider:    "aws",
+				Credentials: missionv1alpha1.CredentialConfig{Name: "team-key", Namespace: keyNamespace, Key: "creds"},
+			}},
+		},
+	}
+}
+
+var _ = Describe("MissionKeys outside the default namespace", Ordered, func() {
+	ctx := context.Background()
+	missionClient := clients.MissionClient{}
+	allowed := newTeamMission("mission-team-a", "team-a")
+	refused := newTeamMission("mission-team-b", "team-a")
+	defaulted := newTeamMission("mission-default-namespace", "")
+
+	BeforeAll(func() {
+		missionClient.Client = k8sClient
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})).Should(Succeed())
+		key := &missionv1alpha1.MissionKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-key", Namespace: "team-a"},
+			Spec: missionv1alpha1.MissionKeySpec{
+				Type:            "aws",
+				Data:            []byte(teamKey),
+				AllowedMissions: []string{allowed.GetName()},
+			},
+		}
+		Expect(k8sClient.Create(ctx, key)).Should(Succeed())
+		defaultKey := &missionv1alpha1.MissionKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-key", Namespace: "default"},
+			Spec:       missionv1alpha1.MissionKeySpec{Type: "aws", Data: []byte(teamKey)},
+		}
+		Expect(k8sClient.Create(ctx, defaultKey)).Should(Succeed())
+		for _, mission := range []*missionv1alpha1.Mission{allowed, refused, defaulted} {
+			Expect(k8sClient.Create(ctx, mission)).Should(Succeed())
+		}
+	})
+
+	It("Should find the key in the namespace of the package", func() {
+		key, err := missionClient.GetMissionKey(ctx, allowed, "team-key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key.GetNamespace()).To(Equal("team-a"))
+	})
+
+	It("Should look in the default namespace when the package names none", func() {
+		Expect(defaulted.Spec.Packages[0].KeyRef()).To(Equal(types.NamespacedName{Name: "team-key", Namespace: "default"}))
+		key, err := missionClient.GetMissionKey(ctx, defaulted, "team-key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key.GetNamespace()).To(Equal("default"))
+	})
+
+	It("Should only let keys outside the default namespace be used when they allow it", func() {
+		key := &missionv1alpha1.MissionKey{ObjectMeta: metav1.ObjectMeta{Name: "team-key", Namespace: "team-a"}}
+		Expect(key.AllowsMission(allowed.GetName())).To(BeFalse())
+		key.Spec.AllowedMissions = []string{missionv1alpha1.AllowAllMissions}
+		Expect(key.AllowsMission(refused.GetName())).To(BeTrue())
+		key.SetNamespace("default")
+		key.Spec.AllowedMissions = nil
+		Expect(key.AllowsMission(refused.GetName())).To(BeTrue())
+	})
+
+	It("Should refuse Missions the key does not allow", func() {
+		_, err := missionClient.GetMissionKey(ctx, refused, "team-key")
+		Expect(err).To(MatchError(ContainSubstring("does not allow Mission mission-team-b")))
+	})
+
+	It("Should keep the Secret and ServiceAccount next to the key", func() {
+		reconciler := &MissionKeyReconciler{
+			MissionClient: clients.MissionClient{Client: managerClient},
+			Scheme:        scheme.Scheme,
+			Recorder:      record.NewFakeRecorder(100),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "team-key", Namespace: "team-a"}}
+		key := &missionv1alpha1.MissionKey{}
+		// The cache of the manager catches up with the Missions created above.
+		Eventually(func(g Gomega) {
+			_, err := reconciler.Reconcile(ctx, request)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(k8sClient.Get(ctx, request.NamespacedName, key)).Should(Succeed())
+			g.Expect(key.Status.Missions).To(Equal([]string{allowed.GetName()}))
+		}).Should(Succeed())
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, request.NamespacedName, secret)).Should(Succeed())
+		Expect(metav1.IsControlledBy(secret, key)).To(BeTrue())
+		Expect(secret.Data[missionv1alpha1.DefaultCredentialsKey]).To(Equal([]byte(teamKey)))
+		serviceAccount := &corev1.ServiceAccount{}
+		Expect(k8sClient.Get(ctx, request.NamespacedName, serviceAccount)).Should(Succeed())
+		Expect(metav1.IsControlledBy(serviceAccount, key)).To(BeTrue())
+
+		Expect(key.Status.GetCondition(missionv1alpha1.TypeSecretSynced).Status).To(Equal(corev1.ConditionTrue))
+	})
+	It("Should leave fields of other field managers on the Secret", func() {
+		reconciler := &MissionKeyReconciler{
+			MissionClient: clients.MissionClient{Client: managerClient},
+			Scheme:        scheme.Scheme,
+			Recorder:      record.NewFakeRecorder(100),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "team-key", Namespace: "team-a"}}
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, request.NamespacedName, secret)).Should(Succeed())
+		secret.Labels = map[string]string{"team": "a"}
+		Expect(k8sClient.Update(ctx, secret)).Should(Succeed())
+		resourceVersion := secret.GetResourceVersion()
+
+		Eventually(func() error {
+			_, err := reconciler.Reconcile(ctx, request)
+			return err
+		}).Should(Succeed())
+		Expect(k8sClient.Get(ctx, request.NamespacedName, secret)).Should(Succeed())
+		Expect(secret.GetLabels()).To(HaveKeyWithValue("team", "a"))
+		Expect(secret.GetResourceVersion()).To(Equal(resourceVersion))
+		managers := []string{}
+		for _, entry := range secret.GetManagedFields() {
+			managers = append(managers, entry.Manager)
+		}
+		Expect(managers).To(ContainElement(clients.FieldManager))
+	})
+})
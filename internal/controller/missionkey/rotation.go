@@ -0,0 +1,139 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package missionkeycontroller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+)
+
+// KeepPreviousSecret copies the Secret of the key to the previous Secret
+// before new credentials replace it, when the key has an overlap window.
+// The end of the window is recorded on the previous Secret. A previous
+// Secret the key does not control is never overwritten.
+func (r *MissionKeyReconciler) KeepPreviousSecret(ctx context.Context, key, resolved *missionv1alpha1.MissionKey) error {
+	if !key.StoresData() || resolved == nil || key.OverlapWindow() == 0 {
+		return nil
+	}
+	current := &v1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: key.GetName(), Namespace: key.GetNamespace()}, current)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data := current.Data[missionv1alpha1.DefaultCredentialsKey]
+	if bytes.Equal(data, resolved.Spec.Data) {
+		return nil
+	}
+	previous := &v1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Name: key.PreviousSecretName(), Namespace: key.GetNamespace()}, previous)
+	if err == nil && !metav1.IsControlledBy(previous, key) {
+		return fmt.Errorf("Secret %s exists and is not controlled by the key, the previous credentials are not kept.", previous.GetName())
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	now := metav1.Now()
+	expiresAt := now.Add(key.OverlapWindow()).UTC().Format(time.RFC3339)
+	previous = &v1.Secret{
+		Data: map[string][]byte{missionv1alpha1.DefaultCredentialsKey: data},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        key.PreviousSecretName(),
+			Namespace:   key.GetNamespace(),
+			Annotations: map[string]string{missionv1alpha1.PreviousExpiresAnnotation: expiresAt},
+		},
+	}
+	if err := r.ApplyObject(ctx, key, previous); err != nil {
+		return err
+	}
+	key.Status.RotatedAt = &now
+	key.Status.PreviousSecret = previous.GetName()
+	message := fmt.Sprintf("Previous credentials kept in Secret %s until %s.", previous.GetName(), expiresAt)
+	r.Recorder.Event(key, "Normal", "Rotated", message)
+	return nil
+}
+
+// ExpirePreviousSecret deletes the previous Secret once the expiry recorded
+// on it has passed. It returns how long the window remains open otherwise.
+func (r *MissionKeyReconciler) ExpirePreviousSecret(ctx context.Context, key *missionv1alpha1.MissionKey, now time.Time) (time.Duration, error) {
+	if key.Status.RotatedAt == nil && key.Status.PreviousSecret == "" {
+		return 0, nil
+	}
+	previous := &v1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: key.PreviousSecretName(), Namespace: key.GetNamespace()}, previous)
+	if k8serrors.IsNotFound(err) {
+		// The cache may not have seen a Secret written moments ago.
+		if key.Status.RotatedAt != nil {
+			if remaining := key.Status.RotatedAt.Add(key.OverlapWindow()).Sub(now); remaining > 0 {
+				return remaining, nil
+			}
+		}
+		clearPreviousSecret(key)
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !metav1.IsControlledBy(previous, key) {
+		clearPreviousSecret(key)
+		return 0, nil
+	}
+	// A missing or malformed expiry ends the window right away.
+	if expiresAt, err := time.Parse(time.RFC3339, previous.GetAnnotations()[missionv1alpha1.PreviousExpiresAnnotation]); err == nil {
+		if remaining := expiresAt.Sub(now); remaining > 0 {
+			return remaining, nil
+		}
+	}
+	if err := r.DeleteOwnedSecret(ctx, key, previous); err != nil {
+		return 0, err
+	}
+	clearPreviousSecret(key)
+	return 0, nil
+}
+
+func clearPreviousSecret(key *missionv1alpha1.MissionKey) {
+	key.Status.RotatedAt = nil
+	key.Status.PreviousSecret = ""
+}
+
+// RequeueAfter returns when the key has to be looked at again: when it
+// starts warning about expiry, when it expires or when its overlap window
+// ends. Zero means no check is due.
+func RequeueAfter(key *missionv1alpha1.MissionKey, now time.Time, overlap time.Duration) time.Duration {
+	next := overlap
+	if remaining, expires := key.ExpiresIn(now); expires {
+		due := remaining - key.ExpiryWarning()
+		if due <= 0 {
+			due = remaining
+		}
+		if due > 0 && (next == 0 || due < next) {
+			next = due
+		}
+	}
+	return next
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package missionkeycontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	types "k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	record "k8s.io/client-go/tools/record"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	interceptor "sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+)
+
+func newRotatingKey(data string, overlap time.Duration) *missionv1alpha1.MissionKey {
+	return &missionv1alpha1.MissionKey{
+		ObjectMeta: metav1.ObjectMeta{Name: "key", Namespace: "default", UID: "uid"},
+		Spec: missionv1alpha1.MissionKeySpec{
+			Type:     "aws",
+			Data:     []byte(data),
+			Rotation: &missionv1alpha1.RotationPolicy{Overlap: &metav1.Duration{Duration: overlap}},
+		},
+	}
+}
+
+func newReconciler(t *testing.T, objects ...runtime.Object) *MissionKeyReconciler {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := missionv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&missionv1alpha1.MissionKey{}).
+		WithIndex(&missionv1alpha1.Mission{}, clients.CredentialsField, clients.IndexCredentials).
+		WithInterceptorFuncs(interceptor.Funcs{Patch: applyAsUpdate}).
+		Build()
+	return &MissionKeyReconciler{
+		MissionClient: clients.MissionClient{Client: c},
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(10),
+	}
+}
+
+// applyAsUpdate stands in for server-side apply, which the fake client does
+// not support, by creating or replacing the applied object.
+func applyAsUpdate(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Patch(ctx, obj, patch, opts...)
+	}
+	existing := obj.DeepCopyObject().(client.Object)
+	err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if k8serrors.IsNotFound(err) {
+		return c.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return c.Update(ctx, obj)
+}
+
+func TestKeepPreviousSecret(t *testing.T) {
+	ctx := context.Background()
+	key := newRotatingKey("new", time.Hour)
+	current := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "key", Namespace: "default"},
+		Data:       map[string][]byte{missionv1alpha1.DefaultCredentialsKey: []byte("old")},
+	}
+	r := newReconciler(t, current)
+	if err := r.KeepPreviousSecret(ctx, key, key); err != nil {
+		t.Fatal(err)
+	}
+	previous := &v1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "key-previous", Namespace: "default"}, previous); err != nil {
+		t.Fatal(err)
+	}
+	if string(previous.Data[missionv1alpha1.DefaultCredentialsKey]) != "old" {
+		t.Errorf("expected the old credentials, got %s", previous.Data[missionv1alpha1.DefaultCredentialsKey])
+	}
+	if key.Status.RotatedAt == nil || key.Status.PreviousSecret != "key-previous" {
+		t.Errorf("expected the rotation to be recorded, got %+v", key.Status)
+	}
+	if _, ok := previous.Annotations[missionv1alpha1.PreviousExpiresAnnotation]; !ok {
+		t.Error("expected the expiry to be recorded on the previous Secret")
+	}
+	if !metav1.IsControlledBy(previous, key) {
+		t.Error("expected the previous Secret to be controlled by the key")
+	}
+
+	// Unchanged credentials are not a rotation.
+	unchanged := newRotatingKey("old", time.Hour)
+	if err := r.KeepPreviousSecret(ctx, unchanged, unchanged); err != nil || unchanged.Status.RotatedAt != nil {
+		t.Errorf("expected no rotation, got %v %v", unchanged.Status.RotatedAt, err)
+	}
+}
+
+func TestKeepPreviousSecretRefusesOtherSecrets(t *testing.T) {
+	ctx := context.Background()
+	key := newRotatingKey("new", time.Hour)
+	current := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "key", Namespace: "default"},
+		Data:       map[string][]byte{missionv1alpha1.DefaultCredentialsKey: []byte("old")},
+	}
+	other := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "key-previous", Namespace: "default"},
+		Data:       map[string][]byte{"other": []byte("data")},
+	}
+	r := newReconciler(t, current, other)
+	if err := r.KeepPreviousSecret(ctx, key, key); err == nil {
+		t.Fatal("expected a Secret the key does not control to be refused")
+	}
+	stored := &v1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "key-previous", Namespace: "default"}, stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored.Data["other"]; !ok || metav1.GetControllerOf(stored) != nil {
+		t.Errorf("expected the Secret to be left alone, got %+v", stored)
+	}
+}
+
+func TestExpirePreviousSecret(t *testing.T) {
+	ctx := context.Background()
+	key := newRotatingKey("new", time.Hour)
+	// The expiry recorded on the Secret decides, not the rotation time.
+	rotatedAt := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	key.Status.RotatedAt = &rotatedAt
+	key.Status.PreviousSecret = "key-previous"
+	isController := true
+	previous := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:            "key-previous",
+		Namespace:       "default",
+		Annotations:     map[string]string{missionv1alpha1.PreviousExpiresAnnotation: time.Now().Add(30 * time.Minute).UTC().Format(time.RFC3339)},
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: missionv1alpha1.GroupVersion.String(), Kind: "MissionKey", Name: "key", UID: "uid", Controller: &isController}},
+	}}
+	r := newReconciler(t, previous)
+
+	remaining, err := r.ExpirePreviousSecret(ctx, key, time.Now())
+	if err != nil || remaining <= 0 {
+		t.Fatalf("expected the window to remain open, got %v %v", remaining, err)
+	}
+	if _, err := r.ExpirePreviousSecret(ctx, key, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if key.Status.RotatedAt != nil || key.Status.PreviousSecret != "" {
+		t.Error("expected the rotation to be cleared")
+	}
+	if err := r.Get(ctx, types.NamespacedName{Name: "key-previous", Namespace: "default"}, previous); err == nil {
+		t.Error("expected the previous Secret to be deleted")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	now := time.Now()
+	key := newRotatingKey("data", 0)
+	if condition := key.ExpiryCondition(now); condition.Reason != "NoExpiry" || RequeueAfter(key, now, 0) != 0 {
+		t.Errorf("expected a key without expiry, got %s", condition.Reason)
+	}
+	expiresAt := metav1.NewTime(now.Add(10 * 24 * time.Hour))
+	key.Spec.Rotation.ExpiresAt = &expiresAt
+	if condition := key.ExpiryCondition(now); condition.Status != v1.ConditionTrue || condition.Reason == "Expiring" {
+		t.Errorf("expected a current key, got %s", condition.Reason)
+	}
+	if next := RequeueAfter(key, now, 0); next != 3*24*time.Hour {
+		t.Errorf("expected a check when warnings start, got %s", next)
+	}
+	if condition := key.ExpiryCondition(now.Add(5 * 24 * time.Hour)); condition.Reason != "Expiring" {
+		t.Errorf("expected an expiring key, got %s", condition.Reason)
+	}
+	if condition := key.ExpiryCondition(now.Add(11 * 24 * time.Hour)); condition.Status != v1.ConditionFalse || !key.Expired(now.Add(11*24*time.Hour)) {
+		t.Errorf("expected an expired key, got %s", condition.Reason)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package missionkeycontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	v1 "k8s.io/api/core/v1"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+)
+
+// Conditions that must all be True for a MissionKey to be Ready.
+var readyConditions = []xpv1.ConditionType{
+	missionv1alpha1.TypeCredentialsValid,
+	missionv1alpha1.TypeCredentialsCurrent,
+	missionv1alpha1.TypeSecretSynced,
+	missionv1alpha1.TypeServiceAccountSynced,
+}
+
+// UnreadyConditions describes every condition that keeps the key from being
+// ready, or returns an empty string if there is none.
+func UnreadyConditions(key *missionv1alpha1.MissionKey) string {
+	var problems []string
+	for _, t := range readyConditions {
+		condition := key.Status.GetCondition(t)
+		if condition.Status == v1.ConditionTrue {
+			continue
+		}
+		if condition.Message != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", t, condition.Message))
+		} else {
+			problems = append(problems, fmt.Sprintf("%s: %s", t, v1.ConditionUnknown))
+		}
+	}
+	return strings.Join(problems, "; ")
+}
+
+// UpdateMissionKeyStatus writes conditions, fingerprint and Missions through
+// the status subresource. The reconcile error is returned so callers can pass
+// it through.
+func (r *MissionKeyReconciler) UpdateMissionKeyStatus(ctx context.Context, key *missionv1alpha1.MissionKey, reconcileErr error) error {
+	key.Status.ObservedGeneration = key.GetGeneration()
+	if reconcileErr != nil {
+		key.Status.SetConditions(xpv1.ReconcileError(reconcileErr))
+	} else {
+		key.Status.SetConditions(xpv1.ReconcileSuccess())
+	}
+	if message := UnreadyConditions(key); message != "" {
+		key.Status.SetConditions(xpv1.Unavailable().WithMessage(message))
+	} else {
+		key.Status.SetConditions(xpv1.Available())
+	}
+	if err := r.Status().Update(ctx, key); err != nil && reconcileErr == nil {
+		return err
+	}
+	return reconcileErr
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package missionkeycontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+)
+
+func newKeyMission(name, keyName string) *missionv1alpha1.Mission {
+	return &missionv1alpha1.Mission{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: missionv1alpha1.MissionSpec{
+			Packages: []missionv1alpha1.PackageConfig{{
+				Provider:    "gcp",
+				Credentials: missionv1alpha1.CredentialConfig{Name: keyName},
+			}},
+		},
+	}
+}
+
+func TestUpdateMissionKeyStatus(t *testing.T) {
+	ctx := context.Background()
+	key := &missionv1alpha1.MissionKey{
+		ObjectMeta: metav1.ObjectMeta{Name: "missionkey-sample-status", Namespace: "default"},
+		Spec:       missionv1alpha1.MissionKeySpec{Type: "gcp", Data: []byte("{}")},
+	}
+	r := newReconciler(t, key,
+		newKeyMission("mission-sample-status", "missionkey-sample-status"),
+		newKeyMission("mission-sample-other", "other-key"),
+	)
+
+	if err := r.ReconcileKeyMissions(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	key.Status.Fingerprint = key.Fingerprint()
+	key.Status.SetConditions(missionv1alpha1.KeyCondition(missionv1alpha1.TypeCredentialsValid, nil))
+	if err := r.UpdateMissionKeyStatus(ctx, key, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := &missionv1alpha1.MissionKey{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(key), stored); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored.Status.Fingerprint, "sha256:") {
+		t.Errorf("unexpected fingerprint %q", stored.Status.Fingerprint)
+	}
+	if len(stored.Status.Missions) != 1 || stored.Status.Missions[0] != "mission-sample-status" {
+		t.Errorf("expected only mission-sample-status, got %v", stored.Status.Missions)
+	}
+	if c := stored.Status.GetCondition(missionv1alpha1.TypeCredentialsValid); c.Status != v1.ConditionTrue {
+		t.Errorf("expected valid credentials, got %+v", c)
+	}
+	if c := stored.Status.GetCondition(xpv1.TypeSynced); c.Status != v1.ConditionTrue {
+		t.Errorf("expected the key to be synced, got %+v", c)
+	}
+	// The Secret and ServiceAccount are not synced yet.
+	ready := stored.Status.GetCondition(xpv1.TypeReady)
+	if ready.Status != v1.ConditionFalse || !strings.Contains(ready.Message, string(missionv1alpha1.TypeSecretSynced)) {
+		t.Errorf("expected the key not to be ready, got %+v", ready)
+	}
+
+	stored.Spec.Data = []byte(`{"rotated": true}`)
+	stored.Generation++
+	if stored.Fingerprint() == key.Status.Fingerprint {
+		t.Error("expected the fingerprint to change with the key")
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+func (r *NetworkReconciler) ReconcileNetwork(ctx context.Context, mission *v1alpha1.Mission, network *networkv1alpha1.Network) error {
+	pkg, err := mission.GetPackage(network.Spec.Provider, network.Spec.MissionRef.MissionKey)
+	if err != nil {
+		r.Recorder.Event(network, "Warning", "Package not found", err.Error())
+		return err
+	}
+	missionKey, err := r.GetMissionKey(ctx, mission, pkg.Credentials.Name)
+	if err != nil {
+		return err
+	}
+	err = r.ReconcileNetworkByProvider(ctx, mission, pkg, missionKey, network)
+	if err != nil {
+		r.Recorder.Event(mission, "Warning", "ProviderConfig not created", "Could not correctly create ProviderConfig resource.")
+		return err
+	}
+	return nil
+}
+
+func (r *NetworkReconciler) ReconcileNetworkByProvider(ctx context.Context, mission *v1alpha1.Mission, pkg *v1alpha1.PackageConfig, missionKey *v1alpha1.MissionKey, network *networkv1alpha1.Network) error {
+	provider, err := providers.Get(pkg.Provider)
+	if err != nil {
+		return err
+	}
+	providerConfig := provider.ProviderConfig(mission, pkg, missionKey)
+	// The ProviderConfig CRD comes with the provider packages, so they are
+	// installed before the ProviderConfig is looked for.
+	if err := r.InstallPackages(ctx, provider, provider.ServicePackages(providers.KindNetwork)...); err != nil {
+		r.Recorder.Event(network, "Warning", "Provider package not ready", err.Error())
+		return err
+	}
+	if err := r.ConfirmProviderConfig(ctx, providerConfig); err != nil {
+		r.Recorder.Event(network, "Warning", "ProviderConfig not found", err.Error())
+		return err
+	}
+	objects, err := provider.Network(providerConfig.GetName(), network)
+	if err != nil {
+		return err
+	}
+	policy := network.Spec.DeletionPolicy
+	if policy == "" {
+		policy = mission.Spec.DeletionPolicy
+	}
+	observed := make([]client.Object, 0, len(objects))
+	for _, object := range objects {
+		utils.SetDeletionPolicy(object, policy)
+		if err := r.ApplyObject(ctx, network, object); err != nil {
+			return err
+		}
+		observed = append(observed, object)
+	}
+	// Subnets and rules removed from or renamed in the spec leave managed
+	// resources behind that nothing applies anymore.
+	if err := r.DeleteUndesired(ctx, network, providers.ManagedTypes(providers.KindNetwork), objects); err != nil {
+		return err
+	}
+	network.Status.Provider = provider.Name()
+	provider.ObserveNetwork(network, observed)
+	return nil
+}
+
+// DeleteNetwork removes the managed resources of the Network and releases its
+// finalizer once all of them are gone. Whether the cloud resources survive
+// is decided by the deletion policy set on each managed resource.
+func (r *NetworkReconciler) DeleteNetwork(ctx context.Context, network *networkv1alpha1.Network) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(network, utils.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+	remaining, err := r.DeleteControlled(ctx, network, providers.ManagedTypes(providers.KindNetwork))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if remaining > 0 {
+		r.Recorder.Event(network, "Normal", "Deleting", fmt.Sprintf("Waiting for %d managed resources to be deleted", remaining))
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	controllerutil.RemoveFinalizer(network, utils.Finalizer)
+	return ctrl.Result{}, r.Update(ctx, network)
+}
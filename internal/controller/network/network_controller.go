@@ -0,0 +1,94 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	record "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	handler "sigs.k8s.io/controller-runtime/pkg/handler"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// NetworkReconciler reconciles a Network object
+type NetworkReconciler struct {
+	clients.MissionClient
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=network.mission-control.apis.io,resources=networks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=network.mission-control.apis.io,resources=networks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=network.mission-control.apis.io,resources=networks/finalizers,verbs=update
+//+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missions;missionkeys,verbs=get;list;watch
+//+kubebuilder:rbac:groups=pkg.crossplane.io,resources=providers;controllerconfigs,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=compute.gcp.upbound.io,resources=networks;subnetworks;firewalls,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=ec2.aws.upbound.io,resources=vpcs;subnets;securitygroups;securitygrouprules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=azure.upbound.io,resources=resourcegroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=network.azure.upbound.io,resources=virtualnetworks;subnets;securitygroups;securityrules;subnetnetworksecuritygroupassociations,verbs=get;list;watch;create;update;patch;delete
+
+func (r *NetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	network := &networkv1alpha1.Network{}
+	err := r.Get(ctx, req.NamespacedName, network)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !network.GetDeletionTimestamp().IsZero() {
+		return r.DeleteNetwork(ctx, network)
+	}
+	if controllerutil.AddFinalizer(network, utils.Finalizer) {
+		if err := r.Update(ctx, network); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	mission, err := r.GetMission(ctx, network.Spec.MissionRef.MissionName)
+	if err != nil {
+		return ctrl.Result{}, utils.UpdateStatus(ctx, r, network, &network.Status.ConditionedStatus, err)
+	}
+	err = r.ReconcileNetwork(ctx, mission, network)
+	return ctrl.Result{}, utils.UpdateStatus(ctx, r, network, &network.Status.ConditionedStatus, err)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NetworkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Networks are reconciled again when their Mission or one of its
+	// MissionKeys changes, found through the indexes of clients.SetupIndexes.
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&networkv1alpha1.Network{}).
+		Watches(&missionv1alpha1.Mission{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForMissionRef(&networkv1alpha1.NetworkList{}))).
+		Watches(&missionv1alpha1.MissionKey{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForMissionRefByKey(&networkv1alpha1.NetworkList{})))
+	// Provider packages are only watched when the operator installs them,
+	// as their CRD is not required otherwise.
+	if r.Packages != nil {
+		builder = builder.Watches(&cpv1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForPackage(&networkv1alpha1.NetworkList{})))
+	}
+	// Managed resources are only watched when their CRD is installed, and
+	// once the provider packages installed by the operator add it.
+	r.Managed = clients.NewManagedWatches(mgr, "Network", &networkv1alpha1.Network{}, providers.KindNetwork)
+	return r.Managed.Complete(builder, r)
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awscomputev1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	azurev1 "github.com/upbound/provider-azure/apis/azure/v1beta1"
+	azurenetworkv1 "github.com/upbound/provider-azure/apis/network/v1beta1"
+	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	gcpv1 "github.com/upbound/provider-gcp/apis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	types "k8s.io/apimachinery/pkg/types"
+	record "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	interceptor "sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+func sampleNetwork(name string) *networkv1alpha1.Network {
+	return &networkv1alpha1.Network{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: networkv1alpha1.NetworkSpec{
+			MissionRef: networkv1alpha1.NetworkMissionRef{
+				MissionName: "mission-sample",
+			},
+			ForProvider: networkv1alpha1.ProviderData{
+				Name:   name,
+				Region: "us-east-1",
+				CIDR:   "10.0.0.0/16",
+				Subnets: []networkv1alpha1.Subnet{
+					{Name: "web", CIDR: "10.0.1.0/24"},
+					{Name: "db", CIDR: "10.0.2.0/24", Region: "us-east-1b"},
+				},
+				FirewallRules: []networkv1alpha1.FirewallRule{
+					{Name: "http", Protocol: "tcp", Ports: []string{"80", "8000-8080"}},
+					{Name: "ping", Protocol: "icmp", SourceRanges: []string{"10.0.0.0/8"}},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Network GCP translation", func() {
+	Context("Converting a stored Network to a VPC network", func() {
+		It("Should create a subnetwork and firewall for every subnet and rule", func() {
+			ctx := context.Background()
+			network := sampleNetwork("network-sample-gcp")
+			network.Spec.ForProvider.Subnets[1].Region = "us-west1"
+			Expect(k8sClient.Create(ctx, network)).Should(Succeed())
+
+			stored := &networkv1alpha1.Network{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: network.GetName()}, stored)).Should(Succeed())
+			vpc, subnetworks, firewalls := stored.Convert2GCP("mission-sample-gcp")
+			Expect(vpc.GetName()).To(Equal("network-sample-gcp"))
+			Expect(*vpc.Spec.ForProvider.AutoCreateSubnetworks).To(BeFalse())
+			Expect(subnetworks).To(HaveLen(2))
+			Expect(subnetworks[0].GetName()).To(Equal("network-sample-gcp-web"))
+			Expect(*subnetworks[0].Spec.ForProvider.Region).To(Equal("us-east-1"))
+			Expect(*subnetworks[1].Spec.ForProvider.Region).To(Equal("us-west1"))
+			Expect(*subnetworks[1].Spec.ForProvider.IPCidrRange).To(Equal("10.0.2.0/24"))
+			Expect(subnetworks[1].Spec.ForProvider.NetworkRef.Name).To(Equal("network-sample-gcp"))
+			Expect(firewalls).To(HaveLen(2))
+			Expect(*firewalls[0].Spec.ForProvider.Allow[0].Protocol).To(Equal("tcp"))
+			Expect(firewalls[0].Spec.ForProvider.Allow[0].Ports).To(HaveLen(2))
+			Expect(*firewalls[0].Spec.ForProvider.SourceRanges[0]).To(Equal("0.0.0.0/0"))
+			Expect(*firewalls[1].Spec.ForProvider.SourceRanges[0]).To(Equal("10.0.0.0/8"))
+			Expect(firewalls[1].Spec.ProviderConfigReference.Name).To(Equal("mission-sample-gcp"))
+		})
+	})
+})
+
+var _ = Describe("Network AWS translation", func() {
+	Context("Converting a Network to a VPC", func() {
+		It("Should create a security group rule for every port range", func() {
+			network := sampleNetwork("network-sample-aws")
+			vpc, subnets, securityGroup, rules := network.Convert2AWS("mission-sample-aws")
+			Expect(*vpc.Spec.ForProvider.CidrBlock).To(Equal("10.0.0.0/16"))
+			Expect(subnets).To(HaveLen(2))
+			Expect(subnets[0].Spec.ForProvider.AvailabilityZone).To(BeNil())
+			Expect(*subnets[1].Spec.ForProvider.AvailabilityZone).To(Equal("us-east-1b"))
+			Expect(subnets[1].Spec.ForProvider.VPCIDRef.Name).To(Equal("network-sample-aws"))
+			Expect(securityGroup.Spec.ForProvider.VPCIDRef.Name).To(Equal("network-sample-aws"))
+			Expect(rules).To(HaveLen(4))
+			Expect(rules[0].GetName()).To(Equal("network-sample-aws-http-0"))
+			Expect(*rules[1].Spec.ForProvider.FromPort).To(Equal(float64(8000)))
+			Expect(*rules[1].Spec.ForProvider.ToPort).To(Equal(float64(8080)))
+			Expect(rules[2].GetName()).To(Equal("network-sample-aws-ping"))
+			Expect(*rules[2].Spec.ForProvider.FromPort).To(Equal(float64(-1)))
+			Expect(rules[2].Spec.ForProvider.SecurityGroupIDRef.Name).To(Equal("network-sample-aws"))
+			Expect(rules[3].GetName()).To(Equal("network-sample-aws-egress"))
+			Expect(*rules[3].Spec.ForProvider.Type).To(Equal("egress"))
+			Expect(*rules[3].Spec.ForProvider.Protocol).To(Equal("-1"))
+			Expect(*rules[3].Spec.ForProvider.CidrBlocks[0]).To(Equal("0.0.0.0/0"))
+		})
+	})
+})
+
+var _ = Describe("Network Azure translation", func() {
+	Context("Converting a Network to a virtual network", func() {
+		It("Should protect every subnet with the network security group", func() {
+			network := sampleNetwork("network-sample-azure")
+			network.Spec.ForProvider.Region = "eastus"
+			network.Spec.ForProvider.Subnets[1].Region = ""
+			resourceGroup, virtualNetwork, subnets, securityGroup, rules, associations := network.Convert2Azure("mission-sample-azure")
+			Expect(resourceGroup.GetName()).To(Equal("network-network-sample-azure"))
+			Expect(*resourceGroup.Spec.ForProvider.Location).To(Equal("eastus"))
+			Expect(*virtualNetwork.Spec.ForProvider.AddressSpace[0]).To(Equal("10.0.0.0/16"))
+			Expect(subnets).To(HaveLen(2))
+			Expect(subnets[0].Spec.ForProvider.VirtualNetworkNameRef.Name).To(Equal("network-sample-azure"))
+			Expect(securityGroup.Spec.ForProvider.ResourceGroupNameRef.Name).To(Equal("network-network-sample-azure"))
+			Expect(rules).To(HaveLen(2))
+			Expect(*rules[0].Spec.ForProvider.Priority).To(Equal(float64(100)))
+			Expect(*rules[0].Spec.ForProvider.Protocol).To(Equal("Tcp"))
+			Expect(rules[0].Spec.ForProvider.DestinationPortRanges).To(HaveLen(2))
+			Expect(*rules[1].Spec.ForProvider.Priority).To(Equal(float64(110)))
+			Expect(*rules[1].Spec.ForProvider.DestinationPortRange).To(Equal("*"))
+			Expect(associations).To(HaveLen(2))
+			Expect(associations[1].Spec.ForProvider.SubnetIDRef.Name).To(Equal("network-sample-azure-db"))
+		})
+	})
+})
+
+var _ = Describe("Network status", func() {
+	Context("Reconciling a Network", func() {
+		var (
+			ctx = context.Background()
+			c   client.Client
+			r   *NetworkReconciler
+		)
+		reconcile := func(network *networkv1alpha1.Network) *networkv1alpha1.Network {
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(network)})
+			Expect(err).To(HaveOccurred())
+			stored := &networkv1alpha1.Network{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(network), stored)).To(Succeed())
+			return stored
+		}
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(networkv1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(missionv1alpha1.AddToScheme(scheme)).To(Succeed())
+			c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&networkv1alpha1.Network{}).Build()
+			r = &NetworkReconciler{
+				MissionClient: clients.MissionClient{Client: c},
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+			}
+		})
+
+		It("Should report a failed reconcile through the status subresource", func() {
+			network := sampleNetwork("network-sample-failed")
+			Expect(c.Create(ctx, network)).To(Succeed())
+
+			stored := reconcile(network)
+			Expect(stored.GetFinalizers()).To(ContainElement(utils.Finalizer))
+			synced := stored.Status.GetCondition(xpv1.TypeSynced)
+			Expect(synced.Status).To(Equal(corev1.ConditionFalse))
+			Expect(synced.Reason).To(Equal(xpv1.ReasonReconcileError))
+			Expect(synced.Message).To(ContainSubstring("mission-sample"))
+			ready := stored.Status.GetCondition(xpv1.TypeReady)
+			Expect(ready.Status).To(Equal(corev1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(xpv1.ReasonUnavailable))
+		})
+		It("Should keep the mirrored Ready condition when a later reconcile fails", func() {
+			network := sampleNetwork("network-sample-failed-ready")
+			Expect(c.Create(ctx, network)).To(Succeed())
+			network.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			Expect(c.Status().Update(ctx, network)).To(Succeed())
+
+			stored := reconcile(network)
+			Expect(stored.Status.GetCondition(xpv1.TypeSynced).Reason).To(Equal(xpv1.ReasonReconcileError))
+			Expect(stored.Status.GetCondition(xpv1.TypeReady).Status).To(Equal(corev1.ConditionTrue))
+		})
+	})
+	Context("Observing a virtual network", func() {
+		It("Should store the network and subnet ids through the status subresource", func() {
+			ctx := context.Background()
+			network := sampleNetwork("network-sample-status")
+			Expect(k8sClient.Create(ctx, network)).Should(Succeed())
+
+			By("Observing an available network")
+			virtualNetwork := &azurenetworkv1.VirtualNetwork{}
+			virtualNetwork.Status.AtProvider.ID = utils.StrPtr("/subscriptions/0/virtualNetworks/network-sample-status")
+			virtualNetwork.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			subnet := &azurenetworkv1.Subnet{ObjectMeta: metav1.ObjectMeta{Name: "network-sample-status-web"}}
+			subnet.Status.AtProvider.ID = utils.StrPtr("/subscriptions/0/subnets/network-sample-status-web")
+			subnet.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			securityGroup := &azurenetworkv1.SecurityGroup{}
+			securityGroup.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			network.Status.Provider = "azure"
+			network.ObserveAzure(virtualNetwork, []*azurenetworkv1.Subnet{subnet}, securityGroup, nil, nil)
+			Expect(k8sClient.Status().Update(ctx, network)).Should(Succeed())
+
+			stored := &networkv1alpha1.Network{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: network.GetName()}, stored)).Should(Succeed())
+			Expect(stored.Status.Provider).To(Equal("azure"))
+			Expect(stored.Status.NetworkID).To(Equal("/subscriptions/0/virtualNetworks/network-sample-status"))
+			Expect(stored.Status.SubnetIDs).To(Equal(map[string]string{"web": "/subscriptions/0/subnets/network-sample-status-web"}))
+			Expect(stored.Status.GetCondition(xpv1.TypeReady).Status).To(Equal(corev1.ConditionTrue))
+		})
+		It("Should not be ready while one of its resources is still being created", func() {
+			network := sampleNetwork("network-sample-creating")
+			vpc := &gcpcomputev1.Network{}
+			vpc.Status.SetConditions(xpv1.Available())
+			network.ObserveGCP(vpc, []*gcpcomputev1.Subnetwork{{}}, nil)
+			Expect(network.Status.GetCondition(xpv1.TypeReady).Reason).To(Equal(xpv1.ReasonCreating))
+
+			aws := sampleNetwork("network-sample-aws-creating")
+			aws.ObserveAWS(&awscomputev1.VPC{}, nil, &awscomputev1.SecurityGroup{}, nil)
+			Expect(aws.Status.GetCondition(xpv1.TypeReady).Reason).To(Equal(xpv1.ReasonCreating))
+		})
+	})
+})
+
+var _ = Describe("Network pruning", func() {
+	Context("Removing a rule from a Network", func() {
+		It("Should delete the managed resource of the removed rule", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			for _, add := range []func(*runtime.Scheme) error{
+				networkv1alpha1.AddToScheme, missionv1alpha1.AddToScheme, gcpv1.AddToScheme,
+				gcpcomputev1.AddToScheme, awscomputev1.AddToScheme, azurev1.AddToScheme, azurenetworkv1.AddToScheme,
+			} {
+				Expect(add(scheme)).To(Succeed())
+			}
+			mission := &missionv1alpha1.Mission{ObjectMeta: metav1.ObjectMeta{Name: "mission-sample"}}
+			pkg := &missionv1alpha1.PackageConfig{Provider: "gcp", ProjectID: "project"}
+			key := &missionv1alpha1.MissionKey{ObjectMeta: metav1.ObjectMeta{Name: "key", Namespace: "default"}}
+			providerConfig := &gcpv1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "mission-sample-gcp"}}
+			network := sampleNetwork("network-sample-pruning")
+			network.UID = "network-sample-pruning"
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(providerConfig, network).
+				// The fake client does not support server-side apply.
+				WithInterceptorFuncs(interceptor.Funcs{Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					if err := c.Create(ctx, obj); !k8serrors.IsAlreadyExists(err) {
+						return err
+					}
+					return nil
+				}})
+			for _, object := range providers.ManagedTypes(providers.KindNetwork) {
+				builder = builder.WithIndex(object, clients.ControllerField, clients.IndexController)
+			}
+			c := builder.Build()
+			r := &NetworkReconciler{
+				MissionClient: clients.MissionClient{Client: c},
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+			}
+
+			By("Applying a firewall for every rule")
+			Expect(r.ReconcileNetworkByProvider(ctx, mission, pkg, key, network)).To(Succeed())
+			firewalls := &gcpcomputev1.FirewallList{}
+			Expect(c.List(ctx, firewalls)).To(Succeed())
+			Expect(firewalls.Items).To(HaveLen(2))
+
+			By("Deleting the firewall of the removed rule only")
+			network.Spec.ForProvider.FirewallRules = network.Spec.ForProvider.FirewallRules[:1]
+			Expect(r.ReconcileNetworkByProvider(ctx, mission, pkg, key, network)).To(Succeed())
+			Expect(c.List(ctx, firewalls)).To(Succeed())
+			Expect(firewalls.Items).To(HaveLen(1))
+			Expect(firewalls.Items[0].GetName()).To(Equal(networkv1alpha1.RuleResourceName("network-sample-pruning", "http")))
+			subnetworks := &gcpcomputev1.SubnetworkList{}
+			Expect(c.List(ctx, subnetworks)).To(Succeed())
+			Expect(subnetworks.Items).To(HaveLen(2))
+		})
+	})
+})
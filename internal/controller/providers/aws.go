@@ -0,0 +1,176 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+
+	awscomputev1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	awsrdsv1 "github.com/upbound/provider-aws/apis/rds/v1beta1"
+	awsstoragev1 "github.com/upbound/provider-aws/apis/s3/v1beta1"
+	awsv1 "github.com/upbound/provider-aws/apis/v1beta1"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+)
+
+func init() {
+	Register(&AWS{})
+}
+
+type AWS struct{}
+
+func (p *AWS) Name() string {
+	return "aws"
+}
+
+func (p *AWS) Package() string {
+	return "provider-family-aws"
+}
+
+func (p *AWS) PackageVersion() string {
+	return "v0.41.0"
+}
+
+func (p *AWS) ServicePackages(kind string) []string {
+	switch kind {
+	case KindVirtualMachine:
+		return []string{"provider-aws-ec2"}
+	case KindStorageBuckets:
+		return []string{"provider-aws-s3"}
+	case KindNetwork:
+		return []string{"provider-aws-ec2"}
+	case KindDatabase:
+		return []string{"provider-aws-rds"}
+	}
+	return nil
+}
+
+func (p *AWS) VerifyMission(mission *missionv1alpha1.Mission, packageId int) error {
+	return mission.AWSVerify()
+}
+
+func (p *AWS) VerifyKey(key *missionv1alpha1.MissionKey) error {
+	return key.AWSVerify()
+}
+
+func (p *AWS) VerifyVirtualMachine(vm *computev1alpha1.VirtualMachine) error {
+	return vm.AWSVerify()
+}
+
+func (p *AWS) VerifyStorageBucket(bucket *storagev1alpha1.StorageBuckets) error {
+	return bucket.AWSVerify()
+}
+
+func (p *AWS) VerifyNetwork(network *networkv1alpha1.Network) error {
+	return network.AWSVerify()
+}
+
+func (p *AWS) VerifyDatabase(database *databasev1alpha1.Database) error {
+	return database.AWSVerify()
+}
+
+func (p *AWS) ProviderConfig(mission *missionv1alpha1.Mission, pkg *missionv1alpha1.PackageConfig, key *missionv1alpha1.MissionKey) client.Object {
+	return mission.Convert2AWS(pkg, key)
+}
+
+func (p *AWS) VirtualMachine(providerConfig string, vm *computev1alpha1.VirtualMachine) ([]client.Object, error) {
+	return []client.Object{vm.Convert2AWS(providerConfig)}, nil
+}
+
+func (p *AWS) ObserveVirtualMachine(vm *computev1alpha1.VirtualMachine, observed []client.Object) {
+	for _, object := range observed {
+		if instance, ok := object.(*awscomputev1.Instance); ok {
+			vm.ObserveAWS(instance)
+		}
+	}
+}
+
+func (p *AWS) StorageBucket(providerConfig string, bucket *storagev1alpha1.StorageBuckets) ([]client.Object, error) {
+	return []client.Object{bucket.Convert2AWS(providerConfig)}, nil
+}
+
+func (p *AWS) ObserveStorageBucket(bucket *storagev1alpha1.StorageBuckets, observed []client.Object) {
+	for _, object := range observed {
+		if managed, ok := object.(*awsstoragev1.Bucket); ok {
+			bucket.ObserveAWS(managed)
+		}
+	}
+}
+
+func (p *AWS) Network(providerConfig string, network *networkv1alpha1.Network) ([]client.Object, error) {
+	vpc, subnets, securityGroup, rules := network.Convert2AWS(providerConfig)
+	objects := []client.Object{vpc}
+	for _, subnet := range subnets {
+		objects = append(objects, subnet)
+	}
+	objects = append(objects, securityGroup)
+	for _, rule := range rules {
+		objects = append(objects, rule)
+	}
+	return objects, nil
+}
+
+func (p *AWS) ObserveNetwork(network *networkv1alpha1.Network, observed []client.Object) {
+	vpc, securityGroup := &awscomputev1.VPC{}, &awscomputev1.SecurityGroup{}
+	var subnets []*awscomputev1.Subnet
+	var rules []*awscomputev1.SecurityGroupRule
+	for _, object := range observed {
+		switch managed := object.(type) {
+		case *awscomputev1.VPC:
+			vpc = managed
+		case *awscomputev1.Subnet:
+			subnets = append(subnets, managed)
+		case *awscomputev1.SecurityGroup:
+			securityGroup = managed
+		case *awscomputev1.SecurityGroupRule:
+			rules = append(rules, managed)
+		}
+	}
+	network.ObserveAWS(vpc, subnets, securityGroup, rules)
+}
+
+func (p *AWS) Database(providerConfig string, database *databasev1alpha1.Database) ([]client.Object, error) {
+	return []client.Object{database.Convert2AWS(providerConfig)}, nil
+}
+
+func (p *AWS) ObserveDatabase(database *databasev1alpha1.Database, observed []client.Object) {
+	for _, object := range observed {
+		if instance, ok := object.(*awsrdsv1.Instance); ok {
+			database.ObserveAWS(instance)
+		}
+	}
+}
+
+func (p *AWS) ManagedTypes(kind string) []client.Object {
+	switch kind {
+	case KindProviderConfig:
+		return []client.Object{&awsv1.ProviderConfig{}}
+	case KindVirtualMachine:
+		return []client.Object{&awscomputev1.Instance{}}
+	case KindStorageBuckets:
+		return []client.Object{&awsstoragev1.Bucket{}}
+	case KindNetwork:
+		return []client.Object{&awscomputev1.VPC{}, &awscomputev1.Subnet{}, &awscomputev1.SecurityGroup{}, &awscomputev1.SecurityGroupRule{}}
+	case KindDatabase:
+		return []client.Object{&awsrdsv1.Instance{}}
+	}
+	return nil
+}
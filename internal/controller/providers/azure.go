@@ -0,0 +1,195 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+
+	azurev1 "github.com/upbound/provider-azure/apis/azure/v1beta1"
+	azurecomputev1 "github.com/upbound/provider-azure/apis/compute/v1beta1"
+	azuredbv1 "github.com/upbound/provider-azure/apis/dbforpostgresql/v1beta1"
+	azurenetworkv1 "github.com/upbound/provider-azure/apis/network/v1beta1"
+	azurestoragev1 "github.com/upbound/provider-azure/apis/storage/v1beta1"
+	azrv1 "github.com/upbound/provider-azure/apis/v1beta1"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+)
+
+func init() {
+	Register(&Azure{})
+}
+
+type Azure struct{}
+
+func (p *Azure) Name() string {
+	return "azure"
+}
+
+func (p *Azure) Package() string {
+	return "provider-family-azure"
+}
+
+func (p *Azure) PackageVersion() string {
+	return "v0.37.1"
+}
+
+func (p *Azure) ServicePackages(kind string) []string {
+	switch kind {
+	case KindVirtualMachine:
+		return []string{"provider-azure-compute", "provider-azure-network"}
+	case KindStorageBuckets:
+		return []string{"provider-azure-storage"}
+	case KindNetwork:
+		return []string{"provider-azure-network"}
+	case KindDatabase:
+		return []string{"provider-azure-dbforpostgresql"}
+	}
+	return nil
+}
+
+func (p *Azure) VerifyMission(mission *missionv1alpha1.Mission, packageId int) error {
+	return mission.AzureVerify()
+}
+
+func (p *Azure) VerifyKey(key *missionv1alpha1.MissionKey) error {
+	return key.AzureVerify()
+}
+
+func (p *Azure) VerifyVirtualMachine(vm *computev1alpha1.VirtualMachine) error {
+	return vm.AzureVerify()
+}
+
+func (p *Azure) VerifyStorageBucket(bucket *storagev1alpha1.StorageBuckets) error {
+	return bucket.AzureVerify()
+}
+
+func (p *Azure) VerifyNetwork(network *networkv1alpha1.Network) error {
+	return network.AzureVerify()
+}
+
+func (p *Azure) VerifyDatabase(database *databasev1alpha1.Database) error {
+	return database.AzureVerify()
+}
+
+func (p *Azure) ProviderConfig(mission *missionv1alpha1.Mission, pkg *missionv1alpha1.PackageConfig, key *missionv1alpha1.MissionKey) client.Object {
+	return mission.Convert2Azure(pkg, key)
+}
+
+func (p *Azure) VirtualMachine(providerConfig string, vm *computev1alpha1.VirtualMachine) ([]client.Object, error) {
+	resourceGroup, nic, linuxvm := vm.Convert2Azure(providerConfig)
+	return []client.Object{resourceGroup, nic, linuxvm}, nil
+}
+
+func (p *Azure) ObserveVirtualMachine(vm *computev1alpha1.VirtualMachine, observed []client.Object) {
+	for _, object := range observed {
+		if linuxvm, ok := object.(*azurecomputev1.LinuxVirtualMachine); ok {
+			vm.ObserveAzure(linuxvm)
+		}
+	}
+}
+
+func (p *Azure) StorageBucket(providerConfig string, bucket *storagev1alpha1.StorageBuckets) ([]client.Object, error) {
+	resourceGroup, account, container, err := bucket.Convert2Azure(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return []client.Object{resourceGroup, account, container}, nil
+}
+
+func (p *Azure) ObserveStorageBucket(bucket *storagev1alpha1.StorageBuckets, observed []client.Object) {
+	account, container := &azurestoragev1.Account{}, &azurestoragev1.Container{}
+	for _, object := range observed {
+		switch managed := object.(type) {
+		case *azurestoragev1.Account:
+			account = managed
+		case *azurestoragev1.Container:
+			container = managed
+		}
+	}
+	bucket.ObserveAzure(account, container)
+}
+
+func (p *Azure) Network(providerConfig string, network *networkv1alpha1.Network) ([]client.Object, error) {
+	resourceGroup, virtualNetwork, subnets, securityGroup, rules, associations := network.Convert2Azure(providerConfig)
+	objects := []client.Object{resourceGroup, virtualNetwork}
+	for _, subnet := range subnets {
+		objects = append(objects, subnet)
+	}
+	objects = append(objects, securityGroup)
+	for _, rule := range rules {
+		objects = append(objects, rule)
+	}
+	for _, association := range associations {
+		objects = append(objects, association)
+	}
+	return objects, nil
+}
+
+func (p *Azure) ObserveNetwork(network *networkv1alpha1.Network, observed []client.Object) {
+	virtualNetwork, securityGroup := &azurenetworkv1.VirtualNetwork{}, &azurenetworkv1.SecurityGroup{}
+	var subnets []*azurenetworkv1.Subnet
+	var rules []*azurenetworkv1.SecurityRule
+	var associations []*azurenetworkv1.SubnetNetworkSecurityGroupAssociation
+	for _, object := range observed {
+		switch managed := object.(type) {
+		case *azurenetworkv1.VirtualNetwork:
+			virtualNetwork = managed
+		case *azurenetworkv1.Subnet:
+			subnets = append(subnets, managed)
+		case *azurenetworkv1.SecurityGroup:
+			securityGroup = managed
+		case *azurenetworkv1.SecurityRule:
+			rules = append(rules, managed)
+		case *azurenetworkv1.SubnetNetworkSecurityGroupAssociation:
+			associations = append(associations, managed)
+		}
+	}
+	network.ObserveAzure(virtualNetwork, subnets, securityGroup, rules, associations)
+}
+
+func (p *Azure) Database(providerConfig string, database *databasev1alpha1.Database) ([]client.Object, error) {
+	resourceGroup, server := database.Convert2Azure(providerConfig)
+	return []client.Object{resourceGroup, server}, nil
+}
+
+func (p *Azure) ObserveDatabase(database *databasev1alpha1.Database, observed []client.Object) {
+	for _, object := range observed {
+		if server, ok := object.(*azuredbv1.FlexibleServer); ok {
+			database.ObserveAzure(server)
+		}
+	}
+}
+
+func (p *Azure) ManagedTypes(kind string) []client.Object {
+	switch kind {
+	case KindProviderConfig:
+		return []client.Object{&azrv1.ProviderConfig{}}
+	case KindVirtualMachine:
+		return []client.Object{&azurev1.ResourceGroup{}, &azurenetworkv1.NetworkInterface{}, &azurecomputev1.LinuxVirtualMachine{}}
+	case KindStorageBuckets:
+		return []client.Object{&azurev1.ResourceGroup{}, &azurestoragev1.Account{}, &azurestoragev1.Container{}}
+	case KindNetwork:
+		return []client.Object{&azurev1.ResourceGroup{}, &azurenetworkv1.VirtualNetwork{}, &azurenetworkv1.Subnet{}, &azurenetworkv1.SecurityGroup{}, &azurenetworkv1.SecurityRule{}, &azurenetworkv1.SubnetNetworkSecurityGroupAssociation{}}
+	case KindDatabase:
+		return []client.Object{&azurev1.ResourceGroup{}, &azuredbv1.FlexibleServer{}}
+	}
+	return nil
+}
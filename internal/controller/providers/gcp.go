@@ -0,0 +1,179 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+
+	gcpcomputev1 "github.com/upbound/provider-gcp/apis/compute/v1beta1"
+	gcpsqlv1 "github.com/upbound/provider-gcp/apis/sql/v1beta1"
+	gcpstoragev1 "github.com/upbound/provider-gcp/apis/storage/v1beta1"
+	gcpv1 "github.com/upbound/provider-gcp/apis/v1beta1"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+)
+
+func init() {
+	Register(&GCP{})
+}
+
+type GCP struct{}
+
+func (p *GCP) Name() string {
+	return "gcp"
+}
+
+func (p *GCP) Package() string {
+	return "provider-family-gcp"
+}
+
+func (p *GCP) PackageVersion() string {
+	return "v0.37.0"
+}
+
+func (p *GCP) ServicePackages(kind string) []string {
+	switch kind {
+	case KindVirtualMachine:
+		return []string{"provider-gcp-compute"}
+	case KindStorageBuckets:
+		return []string{"provider-gcp-storage"}
+	case KindNetwork:
+		return []string{"provider-gcp-compute"}
+	case KindDatabase:
+		return []string{"provider-gcp-sql"}
+	}
+	return nil
+}
+
+func (p *GCP) VerifyMission(mission *missionv1alpha1.Mission, packageId int) error {
+	return mission.GCPVerify(packageId)
+}
+
+func (p *GCP) VerifyKey(key *missionv1alpha1.MissionKey) error {
+	return key.GCPVerify()
+}
+
+func (p *GCP) VerifyVirtualMachine(vm *computev1alpha1.VirtualMachine) error {
+	return vm.GCPVerify()
+}
+
+func (p *GCP) VerifyStorageBucket(bucket *storagev1alpha1.StorageBuckets) error {
+	return bucket.GCPVerify()
+}
+
+func (p *GCP) VerifyNetwork(network *networkv1alpha1.Network) error {
+	return network.GCPVerify()
+}
+
+func (p *GCP) VerifyDatabase(database *databasev1alpha1.Database) error {
+	return database.GCPVerify()
+}
+
+func (p *GCP) ProviderConfig(mission *missionv1alpha1.Mission, pkg *missionv1alpha1.PackageConfig, key *missionv1alpha1.MissionKey) client.Object {
+	return mission.Convert2GCP(pkg, key)
+}
+
+func (p *GCP) VirtualMachine(providerConfig string, vm *computev1alpha1.VirtualMachine) ([]client.Object, error) {
+	return []client.Object{vm.Convert2GCP(providerConfig)}, nil
+}
+
+func (p *GCP) ObserveVirtualMachine(vm *computev1alpha1.VirtualMachine, observed []client.Object) {
+	for _, object := range observed {
+		if instance, ok := object.(*gcpcomputev1.Instance); ok {
+			vm.ObserveGCP(instance)
+		}
+	}
+}
+
+func (p *GCP) StorageBucket(providerConfig string, bucket *storagev1alpha1.StorageBuckets) ([]client.Object, error) {
+	return []client.Object{bucket.Convert2GCP(providerConfig)}, nil
+}
+
+func (p *GCP) ObserveStorageBucket(bucket *storagev1alpha1.StorageBuckets, observed []client.Object) {
+	for _, object := range observed {
+		if managed, ok := object.(*gcpstoragev1.Bucket); ok {
+			bucket.ObserveGCP(managed)
+		}
+	}
+}
+
+func (p *GCP) Network(providerConfig string, network *networkv1alpha1.Network) ([]client.Object, error) {
+	vpc, subnetworks, firewalls := network.Convert2GCP(providerConfig)
+	objects := []client.Object{vpc}
+	for _, subnetwork := range subnetworks {
+		objects = append(objects, subnetwork)
+	}
+	for _, firewall := range firewalls {
+		objects = append(objects, firewall)
+	}
+	return objects, nil
+}
+
+func (p *GCP) ObserveNetwork(network *networkv1alpha1.Network, observed []client.Object) {
+	vpc := &gcpcomputev1.Network{}
+	var subnetworks []*gcpcomputev1.Subnetwork
+	var firewalls []*gcpcomputev1.Firewall
+	for _, object := range observed {
+		switch managed := object.(type) {
+		case *gcpcomputev1.Network:
+			vpc = managed
+		case *gcpcomputev1.Subnetwork:
+			subnetworks = append(subnetworks, managed)
+		case *gcpcomputev1.Firewall:
+			firewalls = append(firewalls, managed)
+		}
+	}
+	network.ObserveGCP(vpc, subnetworks, firewalls)
+}
+
+func (p *GCP) Database(providerConfig string, database *databasev1alpha1.Database) ([]client.Object, error) {
+	instance, user := database.Convert2GCP(providerConfig)
+	return []client.Object{instance, user}, nil
+}
+
+func (p *GCP) ObserveDatabase(database *databasev1alpha1.Database, observed []client.Object) {
+	instance, user := &gcpsqlv1.DatabaseInstance{}, &gcpsqlv1.User{}
+	for _, object := range observed {
+		switch managed := object.(type) {
+		case *gcpsqlv1.DatabaseInstance:
+			instance = managed
+		case *gcpsqlv1.User:
+			user = managed
+		}
+	}
+	database.ObserveGCP(instance, user)
+}
+
+func (p *GCP) ManagedTypes(kind string) []client.Object {
+	switch kind {
+	case KindProviderConfig:
+		return []client.Object{&gcpv1.ProviderConfig{}}
+	case KindVirtualMachine:
+		return []client.Object{&gcpcomputev1.Instance{}}
+	case KindStorageBuckets:
+		return []client.Object{&gcpstoragev1.Bucket{}}
+	case KindNetwork:
+		return []client.Object{&gcpcomputev1.Network{}, &gcpcomputev1.Subnetwork{}, &gcpcomputev1.Firewall{}}
+	case KindDatabase:
+		return []client.Object{&gcpsqlv1.DatabaseInstance{}, &gcpsqlv1.User{}}
+	}
+	return nil
+}
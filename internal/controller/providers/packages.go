@@ -0,0 +1,122 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// DefaultRegistry is the registry the Upbound provider packages are pulled from.
+const DefaultRegistry = "xpkg.upbound.io/upbound"
+
+// Packages decides where the Crossplane provider packages installed by the
+// operator come from.
+type Packages struct {
+	// Registry the packages are pulled from, DefaultRegistry when empty.
+	Registry string
+	// Versions overrides the package version of providers by name. The
+	// family and service packages of a provider always share one version.
+	Versions map[string]string
+}
+
+// Image returns the full reference of a package of the provider.
+func (s *Packages) Image(p Provider, name string) string {
+	registry := strings.TrimSuffix(s.Registry, "/")
+	if registry == "" {
+		registry = DefaultRegistry
+	}
+	version, ok := s.Versions[p.Name()]
+	if !ok || version == "" {
+		version = p.PackageVersion()
+	}
+	return fmt.Sprintf("%s/%s:%s", registry, name, version)
+}
+
+// Provider returns the Crossplane Provider installing a package of the
+// provider, run with the named ControllerConfig when one is given.
+func (s *Packages) Provider(p Provider, name, controllerConfig string) *cpv1.Provider {
+	pkg := &cpv1.Provider{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: cpv1.ProviderSpec{
+			PackageSpec: cpv1.PackageSpec{Package: s.Image(p, name)},
+		},
+	}
+	if controllerConfig != "" {
+		pkg.Spec.ControllerConfigReference = &cpv1.ControllerConfigReference{Name: controllerConfig}
+	}
+	return pkg
+}
+
+// ControllerConfigName is the name of the ControllerConfig that runs the
+// packages of the provider with the cloud identity of a MissionKey.
+func ControllerConfigName(p Provider) string {
+	return "mission-control-" + p.Name()
+}
+
+// ForPackage returns the provider a family or service package belongs to.
+func ForPackage(name string) (Provider, bool) {
+	for _, p := range All() {
+		if p.Package() == name {
+			return p, true
+		}
+		for _, kind := range []string{KindVirtualMachine, KindStorageBuckets, KindNetwork, KindDatabase} {
+			if utils.Contains(p.ServicePackages(kind), name) {
+				return p, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// PackageHealthy returns an error until the package is installed and healthy.
+func PackageHealthy(pkg *cpv1.Provider) error {
+	for _, condition := range []xpv1.ConditionType{cpv1.TypeInstalled, cpv1.TypeHealthy} {
+		if pkg.GetCondition(condition).Status != corev1.ConditionTrue {
+			return fmt.Errorf("Provider package %s is not %s yet", pkg.GetName(), strings.ToLower(string(condition)))
+		}
+	}
+	return nil
+}
+
+// ParseVersions reads package versions by provider name written as
+// "gcp=v0.37.0,aws=v0.41.0".
+func ParseVersions(value string) (map[string]string, error) {
+	versions := map[string]string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, version, ok := strings.Cut(entry, "=")
+		if !ok || version == "" {
+			return nil, fmt.Errorf("Expected provider=version, got %q", entry)
+		}
+		if _, err := Get(name); err != nil {
+			return nil, err
+		}
+		versions[utils.NormalizeProvider(name)] = version
+	}
+	return versions, nil
+}
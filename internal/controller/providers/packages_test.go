@@ -0,0 +1,102 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"reflect"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestPackageImage(t *testing.T) {
+	gcp, _ := Get("gcp")
+	packages := &Packages{}
+	if image := packages.Image(gcp, gcp.Package()); image != "xpkg.upbound.io/upbound/provider-family-gcp:v0.37.0" {
+		t.Errorf("unexpected default image %s", image)
+	}
+	packages = &Packages{Registry: "registry.example.com/mirror/", Versions: map[string]string{"gcp": "v0.40.0"}}
+	provider := packages.Provider(gcp, "provider-gcp-storage", "")
+	if provider.GetName() != "provider-gcp-storage" || provider.Spec.Package != "registry.example.com/mirror/provider-gcp-storage:v0.40.0" {
+		t.Errorf("unexpected Provider %s with package %s", provider.GetName(), provider.Spec.Package)
+	}
+	if provider.Spec.ControllerConfigReference != nil {
+		t.Errorf("expected no ControllerConfig, got %v", provider.Spec.ControllerConfigReference)
+	}
+	provider = packages.Provider(gcp, "provider-gcp-storage", ControllerConfigName(gcp))
+	if ref := provider.Spec.ControllerConfigReference; ref == nil || ref.Name != "mission-control-gcp" {
+		t.Errorf("expected the mission-control-gcp ControllerConfig, got %v", ref)
+	}
+}
+
+func TestServicePackages(t *testing.T) {
+	azure, _ := Get("azure")
+	if !reflect.DeepEqual(azure.ServicePackages(KindVirtualMachine), []string{"provider-azure-compute", "provider-azure-network"}) {
+		t.Fail()
+	}
+	if len(azure.ServicePackages(KindProviderConfig)) != 0 {
+		t.Fail()
+	}
+}
+
+func TestForPackage(t *testing.T) {
+	for name, provider := range map[string]string{"provider-family-aws": "aws", "provider-gcp-storage": "gcp", "provider-azure-network": "azure", "provider-aws-rds": "aws"} {
+		if p, ok := ForPackage(name); !ok || p.Name() != provider {
+			t.Errorf("expected %s to belong to %s", name, provider)
+		}
+	}
+	if _, ok := ForPackage("provider-kubernetes"); ok {
+		t.Fail()
+	}
+}
+
+func TestPackageHealthy(t *testing.T) {
+	pkg := &cpv1.Provider{}
+	pkg.SetName("provider-family-aws")
+	if err := PackageHealthy(pkg); err == nil || err.Error() != "Provider package provider-family-aws is not installed yet" {
+		t.Errorf("unexpected error %v", err)
+	}
+	pkg.SetConditions(cpv1.Active())
+	pkg.SetConditions(cpv1.Unhealthy())
+	if err := PackageHealthy(pkg); err == nil {
+		t.Fail()
+	}
+	pkg.SetConditions(cpv1.Healthy())
+	if err := PackageHealthy(pkg); err != nil {
+		t.Error(err)
+	}
+	pkg.Status.SetConditions(xpv1.Condition{Type: cpv1.TypeInstalled, Status: "False"})
+	if err := PackageHealthy(pkg); err == nil {
+		t.Fail()
+	}
+}
+
+func TestParseVersions(t *testing.T) {
+	versions, err := ParseVersions("GCP=v0.38.0, aws=v0.42.0,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versions, map[string]string{"gcp": "v0.38.0", "aws": "v0.42.0"}) {
+		t.Errorf("unexpected versions %v", versions)
+	}
+	for _, value := range []string{"gcp", "gcp=", "openstack=v1.0.0"} {
+		if _, err := ParseVersions(value); err == nil {
+			t.Errorf("expected %q to fail", value)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"fmt"
+	"sort"
+
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+// Kinds of Mission Control resources a provider creates managed resources for.
+const (
+	KindProviderConfig = "ProviderConfig"
+	KindVirtualMachine = "VirtualMachine"
+	KindStorageBuckets = "StorageBuckets"
+	KindNetwork        = "Network"
+	KindDatabase       = "Database"
+)
+
+// Provider translates Mission Control resources into the resources of a single
+// Crossplane provider family.
+type Provider interface {
+	// Name is the identifier used in Mission packages and MissionKey types.
+	Name() string
+	// Package is the Crossplane provider family package.
+	Package() string
+	// PackageVersion is the release of the provider packages the managed
+	// resource types are taken from.
+	PackageVersion() string
+	// ServicePackages returns the packages of the family that serve the
+	// managed resources created for the given kind.
+	ServicePackages(kind string) []string
+	// VerifyMission checks a Mission package before its ProviderConfig is created.
+	VerifyMission(mission *missionv1alpha1.Mission, packageId int) error
+	// VerifyKey checks the credentials stored in a MissionKey.
+	VerifyKey(key *missionv1alpha1.MissionKey) error
+	// VerifyVirtualMachine checks a VirtualMachine against the naming rules
+	// of the provider.
+	VerifyVirtualMachine(vm *computev1alpha1.VirtualMachine) error
+	// VerifyStorageBucket checks a StorageBuckets against the naming rules
+	// of the provider.
+	VerifyStorageBucket(bucket *storagev1alpha1.StorageBuckets) error
+	// VerifyNetwork checks the names, address ranges and firewall rules of a
+	// Network against the rules of the provider.
+	VerifyNetwork(network *networkv1alpha1.Network) error
+	// VerifyDatabase checks the engine, sizes and names of a Database
+	// against what the provider offers.
+	VerifyDatabase(database *databasev1alpha1.Database) error
+	// ProviderConfig converts a Mission package into a ProviderConfig. The
+	// MissionKey of the package decides how the provider authenticates and
+	// may be nil when it is not known.
+	ProviderConfig(mission *missionv1alpha1.Mission, pkg *missionv1alpha1.PackageConfig, key *missionv1alpha1.MissionKey) client.Object
+	// VirtualMachine returns the managed resources backing a VirtualMachine,
+	// in the order they should be created, using the named ProviderConfig.
+	VirtualMachine(providerConfig string, vm *computev1alpha1.VirtualMachine) ([]client.Object, error)
+	// ObserveVirtualMachine fills the status of a VirtualMachine from its
+	// managed resources, as last read from the cluster.
+	ObserveVirtualMachine(vm *computev1alpha1.VirtualMachine, observed []client.Object)
+	// StorageBucket returns the managed resources backing a StorageBuckets,
+	// in the order they should be created, using the named ProviderConfig.
+	StorageBucket(providerConfig string, bucket *storagev1alpha1.StorageBuckets) ([]client.Object, error)
+	// ObserveStorageBucket fills the status of a StorageBuckets from its
+	// managed resources, as last read from the cluster.
+	ObserveStorageBucket(bucket *storagev1alpha1.StorageBuckets, observed []client.Object)
+	// Network returns the managed resources backing a Network, in the order
+	// they should be created, using the named ProviderConfig.
+	Network(providerConfig string, network *networkv1alpha1.Network) ([]client.Object, error)
+	// ObserveNetwork fills the status of a Network from its managed
+	// resources, as last read from the cluster.
+	ObserveNetwork(network *networkv1alpha1.Network, observed []client.Object)
+	// Database returns the managed resources backing a Database, in the
+	// order they should be created, using the named ProviderConfig.
+	Database(providerConfig string, database *databasev1alpha1.Database) ([]client.Object, error)
+	// ObserveDatabase fills the status of a Database from its managed
+	// resources, as last read from the cluster.
+	ObserveDatabase(database *databasev1alpha1.Database, observed []client.Object)
+	// ManagedTypes returns an empty object of every type created for the
+	// given kind, so that controllers can watch them.
+	ManagedTypes(kind string) []client.Object
+}
+
+var registry = map[string]Provider{}
+
+// Register makes a provider available to every controller. It is meant to
+// be called from init functions.
+func Register(p Provider) {
+	registry[p.Name()] = p
+	utils.AddSupportedProvider(p.Name())
+}
+
+func Get(name string) (Provider, error) {
+	p, ok := registry[utils.NormalizeProvider(name)]
+	if !ok {
+		return nil, fmt.Errorf("Provider %s not known", name)
+	}
+	return p, nil
+}
+
+// All returns the registered providers sorted by name.
+func All() []Provider {
+	var all []Provider
+	for _, p := range registry {
+		all = append(all, p)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name() < all[j].Name() })
+	return all
+}
+
+// ManagedTypes collects the managed resource types of all providers for a kind.
+func ManagedTypes(kind string) []client.Object {
+	var types []client.Object
+	for _, p := range All() {
+		types = append(types, p.ManagedTypes(kind)...)
+	}
+	return types
+}
+
+// ErrNotSupported is returned by providers that have no translation for a kind.
+func ErrNotSupported(provider, kind string) error {
+	return fmt.Errorf("Provider %s does not support %s", provider, kind)
+}
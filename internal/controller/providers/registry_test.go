@@ -0,0 +1,150 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+type FakeProvider struct{}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Package() string {
+	return "provider-fake"
+}
+
+func (p *FakeProvider) PackageVersion() string {
+	return "v0.1.0"
+}
+
+func (p *FakeProvider) ServicePackages(kind string) []string {
+	return nil
+}
+
+func (p *FakeProvider) VerifyMission(mission *missionv1alpha1.Mission, packageId int) error {
+	return nil
+}
+
+func (p *FakeProvider) VerifyKey(key *missionv1alpha1.MissionKey) error {
+	return errors.New("fake keys are never valid")
+}
+
+func (p *FakeProvider) VerifyVirtualMachine(vm *computev1alpha1.VirtualMachine) error {
+	return nil
+}
+
+func (p *FakeProvider) VerifyStorageBucket(bucket *storagev1alpha1.StorageBuckets) error {
+	return nil
+}
+
+func (p *FakeProvider) VerifyNetwork(network *networkv1alpha1.Network) error {
+	return nil
+}
+
+func (p *FakeProvider) VerifyDatabase(database *databasev1alpha1.Database) error {
+	return nil
+}
+
+func (p *FakeProvider) ProviderConfig(mission *missionv1alpha1.Mission, pkg *missionv1alpha1.PackageConfig, key *missionv1alpha1.MissionKey) client.Object {
+	return nil
+}
+
+func (p *FakeProvider) VirtualMachine(providerConfig string, vm *computev1alpha1.VirtualMachine) ([]client.Object, error) {
+	return nil, ErrNotSupported(p.Name(), KindVirtualMachine)
+}
+
+func (p *FakeProvider) ObserveVirtualMachine(vm *computev1alpha1.VirtualMachine, observed []client.Object) {
+}
+
+func (p *FakeProvider) StorageBucket(providerConfig string, bucket *storagev1alpha1.StorageBuckets) ([]client.Object, error) {
+	return nil, ErrNotSupported(p.Name(), KindStorageBuckets)
+}
+
+func (p *FakeProvider) ObserveStorageBucket(bucket *storagev1alpha1.StorageBuckets, observed []client.Object) {
+}
+
+func (p *FakeProvider) Network(providerConfig string, network *networkv1alpha1.Network) ([]client.Object, error) {
+	return nil, ErrNotSupported(p.Name(), KindNetwork)
+}
+
+func (p *FakeProvider) ObserveNetwork(network *networkv1alpha1.Network, observed []client.Object) {
+}
+
+func (p *FakeProvider) Database(providerConfig string, database *databasev1alpha1.Database) ([]client.Object, error) {
+	return nil, ErrNotSupported(p.Name(), KindDatabase)
+}
+
+func (p *FakeProvider) ObserveDatabase(database *databasev1alpha1.Database, observed []client.Object) {
+}
+
+func (p *FakeProvider) ManagedTypes(kind string) []client.Object {
+	return nil
+}
+
+func TestBuiltinProviders(t *testing.T) {
+	for _, name := range []string{"gcp", "aws", "azure", "GCP"} {
+		if _, err := Get(name); err != nil {
+			t.Errorf("provider %s not registered: %v", name, err)
+		}
+	}
+	if _, err := Get("openstack"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(&FakeProvider{})
+	p, err := Get("fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utils.Contains(utils.GetSupportedProviders(), "fake") {
+		t.Fail()
+	}
+	if _, err := p.VirtualMachine("", &computev1alpha1.VirtualMachine{}); err == nil {
+		t.Fail()
+	}
+	names := []string{}
+	for _, provider := range All() {
+		names = append(names, provider.Name())
+	}
+	if !utils.SameList(names, []string{"aws", "azure", "fake", "gcp"}) {
+		t.Fail()
+	}
+}
+
+func TestManagedTypes(t *testing.T) {
+	if len(ManagedTypes(KindProviderConfig)) < 3 {
+		t.Fail()
+	}
+	if len(ManagedTypes("Unknown")) != 0 {
+		t.Fail()
+	}
+}
@@ -18,30 +18,30 @@ package storage
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"reflect"
+	"time"
 
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	types "k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	cpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	v1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
 	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
-
-	awsstoragev1 "github.com/upbound/provider-aws/apis/s3/v1beta1"
-	gcpstoragev1 "github.com/upbound/provider-gcp/apis/storage/v1beta1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
 )
 
 func (r *StorageBucketsReconciler) ReconcileStorageBucket(ctx context.Context, mission *v1alpha1.Mission, bucket *storagev1alpha1.StorageBuckets) error {
-	keyName := bucket.Spec.MissionRef.MissionKey
-	missionKey, err := r.GetMissionKey(ctx, mission, keyName)
+	pkg, err := mission.GetPackage("", bucket.Spec.MissionRef.MissionKey)
 	if err != nil {
+		r.Recorder.Event(bucket, "Warning", "Package not found", err.Error())
 		return err
 	}
-	err = r.ReconcileStorageBucketByProvider(ctx, mission, missionKey, bucket)
+	missionKey, err := r.GetMissionKey(ctx, mission, pkg.Credentials.Name)
+	if err != nil {
+		return err
+	}
+	err = r.ReconcileStorageBucketByProvider(ctx, mission, pkg, missionKey, bucket)
 	if err != nil {
 		r.Recorder.Event(mission, "Warning", "ProviderConfig not created", "Could not correctly create ProviderConfig resource.")
 		return err
@@ -49,85 +49,58 @@ func (r *StorageBucketsReconciler) ReconcileStorageBucket(ctx context.Context, m
 	return nil
 }
 
-func (r *StorageBucketsReconciler) ReconcileStorageBucketByProvider(ctx context.Context, mission *v1alpha1.Mission, missionKey *v1alpha1.MissionKey, bucket *storagev1alpha1.StorageBuckets) error {
-	var err error
-	provider := missionKey.Spec.Type
-	if provider == "gcp" {
-		err = r.GetStorageBucketGCP(ctx, mission, bucket)
-	} else if provider == "aws" {
-		err = r.GetStorageBucketAWS(ctx, mission, bucket)
-	} else {
-		message := fmt.Sprintf("Provider %s not known", provider)
-		err = errors.New(message)
-	}
+func (r *StorageBucketsReconciler) ReconcileStorageBucketByProvider(ctx context.Context, mission *v1alpha1.Mission, pkg *v1alpha1.PackageConfig, missionKey *v1alpha1.MissionKey, bucket *storagev1alpha1.StorageBuckets) error {
+	provider, err := providers.Get(pkg.Provider)
 	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func (r *StorageBucketsReconciler) GetStorageBucketGCP(ctx context.Context, mission *v1alpha1.Mission, bucket *storagev1alpha1.StorageBuckets) error {
-	currentgcpbucket := gcpstoragev1.Bucket{}
-	gcpbucket := gcpstoragev1.Bucket{
-		ObjectMeta: v1.ObjectMeta{
-			Name: bucket.Spec.ForProvider.Name,
-		},
-		Spec: gcpstoragev1.BucketSpec{
-			ForProvider: gcpstoragev1.BucketParameters{
-				Location: &bucket.Spec.ForProvider.Location,
-			},
-			ResourceSpec: cpcommonv1.ResourceSpec{
-				ProviderConfigReference: &cpcommonv1.Reference{
-					Name: mission.GetName() + "-gcp",
-				},
-			},
-		},
+	providerConfig := provider.ProviderConfig(mission, pkg, missionKey)
+	// The ProviderConfig CRD comes with the provider packages, so they are
+	// installed before the ProviderConfig is looked for.
+	if err := r.InstallPackages(ctx, provider, provider.ServicePackages(providers.KindStorageBuckets)...); err != nil {
+		r.Recorder.Event(bucket, "Warning", "Provider package not ready", err.Error())
+		return err
 	}
-	if err := controllerutil.SetControllerReference(bucket, &gcpbucket, r.Scheme); err != nil {
+	if err := r.ConfirmProviderConfig(ctx, providerConfig); err != nil {
+		r.Recorder.Event(bucket, "Warning", "ProviderConfig not found", err.Error())
 		return err
 	}
-	err := r.Get(ctx, types.NamespacedName{Name: bucket.Spec.ForProvider.Name}, &currentgcpbucket)
+	objects, err := provider.StorageBucket(providerConfig.GetName(), bucket)
 	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			return r.Create(ctx, &gcpbucket)
-		}
 		return err
 	}
-	if reflect.DeepEqual(currentgcpbucket.Spec, gcpbucket.Spec) {
-		return nil
+	policy := bucket.Spec.DeletionPolicy
+	if policy == "" {
+		policy = mission.Spec.DeletionPolicy
+	}
+	observed := make([]client.Object, 0, len(objects))
+	for _, object := range objects {
+		utils.SetDeletionPolicy(object, policy)
+		if err := r.ApplyObject(ctx, bucket, object); err != nil {
+			return err
+		}
+		observed = append(observed, object)
 	}
-	return r.Update(ctx, &currentgcpbucket)
+	bucket.Status.Provider = provider.Name()
+	provider.ObserveStorageBucket(bucket, observed)
+	return nil
 }
 
-func (r *StorageBucketsReconciler) GetStorageBucketAWS(ctx context.Context, mission *v1alpha1.Mission, bucket *storagev1alpha1.StorageBuckets) error {
-	currentawsbucket := awsstoragev1.Bucket{}
-	awsbucket := awsstoragev1.Bucket{
-		ObjectMeta: v1.ObjectMeta{
-			Name: bucket.Spec.ForProvider.Name,
-		},
-		Spec: awsstoragev1.BucketSpec{
-			ForProvider: awsstoragev1.BucketParameters{
-				Region: &bucket.Spec.ForProvider.Location,
-			},
-			ResourceSpec: cpcommonv1.ResourceSpec{
-				ProviderConfigReference: &cpcommonv1.Reference{
-					Name: mission.GetName() + "-aws",
-				},
-			},
-		},
+// DeleteStorageBucket removes the managed resources of the bucket and releases its
+// finalizer once all of them are gone. Whether the cloud resources survive
+// is decided by the deletion policy set on each managed resource.
+func (r *StorageBucketsReconciler) DeleteStorageBucket(ctx context.Context, bucket *storagev1alpha1.StorageBuckets) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(bucket, utils.Finalizer) {
+		return ctrl.Result{}, nil
 	}
-	if err := controllerutil.SetControllerReference(bucket, &awsbucket, r.Scheme); err != nil {
-		return err
-	}
-	err := r.Get(ctx, types.NamespacedName{Name: bucket.Spec.ForProvider.Name}, &currentawsbucket)
+	remaining, err := r.DeleteControlled(ctx, bucket, providers.ManagedTypes(providers.KindStorageBuckets))
 	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			return r.Create(ctx, &awsbucket)
-		}
-		return err
+		return ctrl.Result{}, err
 	}
-	if reflect.DeepEqual(currentawsbucket.Spec, awsbucket.Spec) {
-		return nil
+	if remaining > 0 {
+		r.Recorder.Event(bucket, "Normal", "Deleting", fmt.Sprintf("Waiting for %d managed resources to be deleted", remaining))
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
-	return r.Update(ctx, &currentawsbucket)
+	controllerutil.RemoveFinalizer(bucket, utils.Finalizer)
+	return ctrl.Result{}, r.Update(ctx, bucket)
 }
@@ -19,15 +19,19 @@ package storage
 import (
 	"context"
 
+	cpv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	record "k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	handler "sigs.k8s.io/controller-runtime/pkg/handler"
 
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
 	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
 	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
-
-	awsstoragev1 "github.com/upbound/provider-aws/apis/s3/v1beta1"
-	gcpstoragev1 "github.com/upbound/provider-gcp/apis/storage/v1beta1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
 )
 
 // StorageBucketsReconciler reconciles a StorageBuckets object
@@ -40,27 +44,50 @@ type StorageBucketsReconciler struct {
 //+kubebuilder:rbac:groups=storage.mission-control.apis.io,resources=storagebuckets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=storage.mission-control.apis.io,resources=storagebuckets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=storage.mission-control.apis.io,resources=storagebuckets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=mission.mission-control.apis.io,resources=missions;missionkeys,verbs=get;list;watch
+//+kubebuilder:rbac:groups=pkg.crossplane.io,resources=providers;controllerconfigs,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=storage.gcp.upbound.io;s3.aws.upbound.io,resources=buckets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=azure.upbound.io,resources=resourcegroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=storage.azure.upbound.io,resources=accounts;containers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *StorageBucketsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	bucket := &storagev1alpha1.StorageBuckets{}
 	err := r.Get(ctx, req.NamespacedName, bucket)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !bucket.GetDeletionTimestamp().IsZero() {
+		return r.DeleteStorageBucket(ctx, bucket)
+	}
+	if controllerutil.AddFinalizer(bucket, utils.Finalizer) {
+		if err := r.Update(ctx, bucket); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	mission, err := r.GetMission(ctx, bucket.Spec.MissionRef.MissionName)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, utils.UpdateStatus(ctx, r, bucket, &bucket.Status.ConditionedStatus, err)
 	}
 	err = r.ReconcileStorageBucket(ctx, mission, bucket)
-	return ctrl.Result{}, err
+	return ctrl.Result{}, utils.UpdateStatus(ctx, r, bucket, &bucket.Status.ConditionedStatus, err)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *StorageBucketsReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// StorageBuckets are reconciled again when their Mission or one of its
+	// MissionKeys changes, found through the indexes of clients.SetupIndexes.
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&storagev1alpha1.StorageBuckets{}).
-		Owns(&gcpstoragev1.Bucket{}).
-		Owns(&awsstoragev1.Bucket{}).
-		Complete(r)
+		Watches(&missionv1alpha1.Mission{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForMissionRef(&storagev1alpha1.StorageBucketsList{}))).
+		Watches(&missionv1alpha1.MissionKey{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForMissionRefByKey(&storagev1alpha1.StorageBucketsList{})))
+	// Provider packages are only watched when the operator installs them,
+	// as their CRD is not required otherwise.
+	if r.Packages != nil {
+		builder = builder.Watches(&cpv1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForPackage(&storagev1alpha1.StorageBucketsList{})))
+	}
+	// Managed resources are only watched when their CRD is installed, and
+	// once the provider packages installed by the operator add it.
+	r.Managed = clients.NewManagedWatches(mgr, "StorageBuckets", &storagev1alpha1.StorageBuckets{}, providers.KindStorageBuckets)
+	return r.Managed.Complete(builder, r)
 }
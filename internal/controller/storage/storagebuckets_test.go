@@ -0,0 +1,126 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gcpstoragev1 "github.com/upbound/provider-gcp/apis/storage/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	record "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+var _ = Describe("StorageBuckets status", func() {
+	Context("Reconciling a StorageBuckets", func() {
+		It("Should report a failed reconcile through the status subresource", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(storagev1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(missionv1alpha1.AddToScheme(scheme)).To(Succeed())
+			bucket := &storagev1alpha1.StorageBuckets{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "storagebuckets-sample-status",
+				},
+				Spec: storagev1alpha1.StorageBucketsSpec{
+					MissionRef: storagev1alpha1.StorageBucketMissionRef{
+						MissionName: "mission-sample-missing",
+					},
+					ForProvider: storagev1alpha1.ProviderData{
+						Name:     "samplebucket-status",
+						Location: "US",
+					},
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(bucket).WithObjects(bucket).Build()
+			r := &StorageBucketsReconciler{
+				MissionClient: clients.MissionClient{Client: c},
+				Scheme:        scheme,
+				Recorder:      record.NewFakeRecorder(10),
+			}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(bucket)})
+			Expect(err).To(HaveOccurred())
+			stored := &storagev1alpha1.StorageBuckets{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(bucket), stored)).To(Succeed())
+			Expect(stored.GetFinalizers()).To(ContainElement(utils.Finalizer))
+			synced := stored.Status.GetCondition(xpv1.TypeSynced)
+			Expect(synced.Status).To(Equal(corev1.ConditionFalse))
+			Expect(synced.Reason).To(Equal(xpv1.ReasonReconcileError))
+			Expect(stored.Status.GetCondition(xpv1.TypeReady).Reason).To(Equal(xpv1.ReasonUnavailable))
+		})
+	})
+	Context("Observing a Cloud Storage bucket", func() {
+		It("Should store the bucket location and conditions", func() {
+			managed := &gcpstoragev1.Bucket{}
+			managed.Status.AtProvider = gcpstoragev1.BucketObservation{
+				Location: utils.StrPtr("US"),
+				SelfLink: utils.StrPtr("https://www.googleapis.com/storage/v1/b/samplebucket-status"),
+				URL:      utils.StrPtr("gs://samplebucket-status"),
+			}
+			managed.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			bucket := &storagev1alpha1.StorageBuckets{}
+			bucket.ObserveGCP(managed)
+			Expect(bucket.Status.URL).To(Equal("gs://samplebucket-status"))
+			Expect(bucket.Status.Region).To(Equal("US"))
+			Expect(bucket.Status.SelfLink).To(Equal("https://www.googleapis.com/storage/v1/b/samplebucket-status"))
+			Expect(bucket.Status.GetCondition(xpv1.TypeReady).Status).To(Equal(corev1.ConditionTrue))
+		})
+	})
+})
+
+var _ = Describe("StorageBuckets Azure translation", func() {
+	Context("Converting a StorageBuckets to a storage account and container", func() {
+		It("Should create the account in a resource group of its own", func() {
+			bucket := &storagev1alpha1.StorageBuckets{
+				Spec: storagev1alpha1.StorageBucketsSpec{
+					ForProvider: storagev1alpha1.ProviderData{
+						Name:     "sample-bucket-azure",
+						Location: "eastus",
+					},
+				},
+			}
+			Expect(bucket.AzureVerify()).To(Succeed())
+			resourceGroup, account, container, err := bucket.Convert2Azure("mission-sample-azure-azure")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resourceGroup.GetName()).To(Equal("storagebuckets-sample-bucket-azure"))
+			Expect(account.GetName()).To(Equal("samplebucketazure"))
+			Expect(account.Spec.ForProvider.ResourceGroupNameRef.Name).To(Equal("storagebuckets-sample-bucket-azure"))
+			Expect(container.GetName()).To(Equal("sample-bucket-azure"))
+			Expect(container.Spec.ForProvider.StorageAccountNameRef.Name).To(Equal("samplebucketazure"))
+		})
+		It("Should refuse names too short for a storage account", func() {
+			_, err := storagev1alpha1.AzureStorageAccountName("a-b")
+			Expect(err).To(HaveOccurred())
+			name, err := storagev1alpha1.AzureStorageAccountName("A-Very.Long-Bucket-Name-For-Azure")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(Equal("averylongbucketnameforaz"))
+		})
+	})
+})
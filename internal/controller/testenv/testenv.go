@@ -0,0 +1,52 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testenv starts the envtest API server used by the test suites of
+// the resource controllers.
+package testenv
+
+import (
+	"path/filepath"
+
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	scheme "k8s.io/client-go/kubernetes/scheme"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+	envtest "sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// Start runs an API server with the CRDs of the operator and returns it with
+// a client whose scheme knows the given API groups. The CRD path is relative
+// to the controller packages in internal/controller.
+func Start(addToScheme ...func(*runtime.Scheme) error) (*envtest.Environment, client.Client, error) {
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, add := range addToScheme {
+		if err := add(scheme.Scheme); err != nil {
+			return env, nil, err
+		}
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		return env, nil, err
+	}
+	return env, c, nil
+}
@@ -17,10 +17,18 @@ limitations under the License.
 package utils
 
 import (
+	"crypto/rand"
 	"errors"
+	"math/big"
 	"reflect"
 	"slices"
 	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	resource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Slices utilities
@@ -78,26 +86,103 @@ func RemoveString(slice []string, s string) (result []string) {
 	return
 }
 
+// StrPtr returns a pointer to s, as the optional fields of managed
+// resources take.
+func StrPtr(s string) *string {
+	return &s
+}
+
+// StrValue returns the string s points to, or an empty string when s is nil.
+func StrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// AzureResourceGroupName returns the name of the resource group created for
+// a resource of the given kind. Resources of different kinds may share a
+// name, so the kind is part of it.
+func AzureResourceGroupName(kind, name string) string {
+	return strings.ToLower(kind) + "-" + name
+}
+
+// passwordCharacters are the characters of generated passwords. Symbols are
+// left out as every provider forbids a different set of them.
+const passwordCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// GeneratePassword returns a random password of the given length that has
+// lowercase and uppercase letters as well as digits.
+func GeneratePassword(length int) (string, error) {
+	if length < 3 {
+		return "", errors.New("Passwords need at least 3 characters")
+	}
+	count := big.NewInt(int64(len(passwordCharacters)))
+	password := make([]byte, length)
+	for {
+		for i := range password {
+			n, err := rand.Int(rand.Reader, count)
+			if err != nil {
+				return "", err
+			}
+			password[i] = passwordCharacters[n.Int64()]
+		}
+		if strings.ContainsAny(string(password), passwordCharacters[:26]) &&
+			strings.ContainsAny(string(password), passwordCharacters[26:52]) &&
+			strings.ContainsAny(string(password), passwordCharacters[52:]) {
+			return string(password), nil
+		}
+	}
+}
+
 // Object utilities
 
-func GetValueOf(objPtr any, field string) reflect.Value {
-	value := reflect.ValueOf(objPtr).Elem()
-	val := reflect.Indirect(value).FieldByName(field)
-	if val.IsValid() {
-		return val
+// NewObjectOf returns an empty object of the same type as obj, to be used as
+// the target of a Get.
+func NewObjectOf(obj client.Object) client.Object {
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+}
+
+// SetDeletionPolicy sets policy on obj if it is a managed resource that can be
+// orphaned. An empty policy leaves the Crossplane default in place.
+func SetDeletionPolicy(obj client.Object, policy xpv1.DeletionPolicy) {
+	orphanable, ok := obj.(resource.Orphanable)
+	if !ok || policy == "" {
+		return
 	}
-	return reflect.Value{}
+	orphanable.SetDeletionPolicy(policy)
 }
 
-func SetValueOf(objPtr, newObjPtr any, field string) error {
-	val := GetValueOf(objPtr, field)
-	newVal := GetValueOf(newObjPtr, field)
-	if reflect.TypeOf(val) != reflect.TypeOf(newVal) {
-		return errors.New("Issue setting new value")
+// MirrorConditions copies the Ready and Synced conditions of a managed
+// resource onto status. Conditions the resource has not reported yet are
+// replaced with Creating and ReconcileSuccess.
+func MirrorConditions(status *xpv1.ConditionedStatus, source client.Object) {
+	ready, synced := xpv1.Creating(), xpv1.ReconcileSuccess()
+	if conditioned, ok := source.(resource.Conditioned); ok {
+		if c := conditioned.GetCondition(xpv1.TypeReady); c.Status != corev1.ConditionUnknown {
+			ready = c
+		}
+		if c := conditioned.GetCondition(xpv1.TypeSynced); c.Status != corev1.ConditionUnknown {
+			synced = c
+		}
 	}
-	if !val.CanSet() {
-		return errors.New("Could not set new value")
+	status.SetConditions(ready, synced)
+}
+
+// MirrorLeastReady mirrors the conditions of several managed resources that
+// together back one resource. The first of them that is not ready, or not
+// synced, decides the respective condition.
+func MirrorLeastReady(status *xpv1.ConditionedStatus, sources ...client.Object) {
+	ready, synced := xpv1.Creating(), xpv1.ReconcileSuccess()
+	for i, source := range sources {
+		mirrored := xpv1.ConditionedStatus{}
+		MirrorConditions(&mirrored, source)
+		if c := mirrored.GetCondition(xpv1.TypeReady); i == 0 || (ready.Status == corev1.ConditionTrue && c.Status != corev1.ConditionTrue) {
+			ready = c
+		}
+		if c := mirrored.GetCondition(xpv1.TypeSynced); i == 0 || (synced.Status == corev1.ConditionTrue && c.Status != corev1.ConditionTrue) {
+			synced = c
+		}
 	}
-	val.Set(newVal)
-	return nil
+	status.SetConditions(ready, synced)
 }
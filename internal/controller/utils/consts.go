@@ -16,12 +16,35 @@ limitations under the License.
 
 package utils
 
-var ProviderMapping = map[string]string{
-	"gcp":   "provider-gcp-family",
-	"aws":   "provider-aws-family",
-	"azure": "provider-azure-family",
+import (
+	"slices"
+	"strings"
+)
+
+// Names of the providers registered through the providers package. They are
+// kept here so that API types can validate against them without importing
+// the provider implementations.
+var supportedProviders []string
+
+func AddSupportedProvider(name string) {
+	if Contains(supportedProviders, name) {
+		return
+	}
+	supportedProviders = append(supportedProviders, name)
+	slices.Sort(supportedProviders)
 }
 
+// GetSupportedProviders returns a copy of the registered provider names.
 func GetSupportedProviders() []string {
-	return GetValues(ProviderMapping)
+	return slices.Clone(supportedProviders)
 }
+
+// NormalizeProvider returns a provider identifier in the form it is
+// registered and compared with.
+func NormalizeProvider(provider string) string {
+	return strings.ToLower(strings.TrimSpace(provider))
+}
+
+// Finalizer is added to every resource reconciled by the operator so that
+// the cloud resources it created are cleaned up before it is removed.
+const Finalizer = "mission-control.apis.io/finalizer"
@@ -18,17 +18,38 @@ package utils
 
 import (
 	"context"
-	"os"
+	"fmt"
 
-	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	clientcmd "k8s.io/client-go/tools/clientcmd"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func ConfirmCRD(ctx context.Context, crdNameVersion string) error {
-	// Confirm that specified CRD is installed in the kubernetes cluster
-	clientConfig, _ := clientcmd.BuildConfigFromFlags("", os.Getenv("HOME")+"/.kube/config")
-	clientset, _ := apiextensionsclientset.NewForConfig(clientConfig)
-	_, err := clientset.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crdNameVersion, v1.GetOptions{})
+// ConfirmCRD confirms that the cluster serves the given kind, as installed by
+// its CRD. Lookups go through the RESTMapper of the manager, which caches
+// discovery and refreshes it when a kind is not found.
+func ConfirmCRD(mapper meta.RESTMapper, gvk schema.GroupVersionKind) error {
+	_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("CRD for %s is not installed", gvk.GroupKind())
+	}
 	return err
 }
+
+// UpdateStatus records the outcome of a reconcile of obj, whose conditions
+// are status, on top of the conditions mirrored from its managed resources.
+// The reconcile error is returned so callers can pass it through.
+func UpdateStatus(ctx context.Context, c client.StatusClient, obj client.Object, status *xpv1.ConditionedStatus, reconcileErr error) error {
+	if reconcileErr != nil {
+		status.SetConditions(xpv1.ReconcileError(reconcileErr))
+		if status.GetCondition(xpv1.TypeReady).Status == corev1.ConditionUnknown {
+			status.SetConditions(xpv1.Unavailable())
+		}
+	}
+	if err := c.Status().Update(ctx, obj); err != nil && reconcileErr == nil {
+		return err
+	}
+	return reconcileErr
+}
@@ -17,22 +17,17 @@ limitations under the License.
 package utils
 
 import (
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
-)
-
-type TestSubObject struct {
-	SubObject string
-}
 
-type TestObject struct {
-	StringObject  string
-	IntObject     int
-	ListObject    []string
-	MapObject     map[string]string
-	StructObject  TestSubObject
-	PointerObject *TestSubObject
-}
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	fake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	v1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+)
 
 func TestGetValues(t *testing.T) {
 	result := GetValues(map[string]string{"a": "A"})
@@ -137,90 +132,107 @@ func TestRemoveString(t *testing.T) {
 	}
 }
 
-func TestGetValueOf(t *testing.T) {
-	obj := &TestObject{
-		"string", 1, []string{"list", "object"}, map[string]string{"map": "object"}, TestSubObject{"struct"}, &TestSubObject{"pointer"},
+func TestStrValue(t *testing.T) {
+	if StrValue(nil) != "" {
+		t.Error("expected nil strings to be empty")
 	}
-	result := GetValueOf(obj, "StringObject")
-	if result.Kind() != reflect.String {
-		t.Fail()
-	}
-	if result.String() != "string" {
-		t.Fail()
+	if StrValue(StrPtr("a")) != "a" {
+		t.Error("expected the value StrPtr points to")
 	}
-	result = GetValueOf(obj, "IntObject")
-	if result.Kind() != reflect.Int {
-		t.Fail()
+}
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := GeneratePassword(24)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if result.Int() != 1 {
-		t.Fail()
+	if len(password) != 24 || !strings.ContainsAny(password, "0123456789") || strings.ToLower(password) == password || strings.ToUpper(password) == password {
+		t.Errorf("unexpected password %q", password)
 	}
-	result = GetValueOf(obj, "ListObject")
-	if result.Kind() != reflect.Slice {
-		t.Fail()
+	if other, _ := GeneratePassword(24); other == password {
+		t.Error("expected a different password on every call")
 	}
-	if !SameList(result.Interface().([]string), []string{"list", "object"}) {
-		t.Fail()
+	if _, err := GeneratePassword(2); err == nil {
+		t.Error("expected an error for a too short password")
 	}
-	result = GetValueOf(obj, "MapObject")
-	if result.Kind() != reflect.Map {
+}
+
+func TestNewObjectOf(t *testing.T) {
+	secret := &v1.Secret{Data: map[string][]byte{"creds": []byte("data")}}
+	secret.SetName("secret")
+	result, ok := NewObjectOf(secret).(*v1.Secret)
+	if !ok {
 		t.Fail()
 	}
-	if result.Interface().(map[string]string)["map"] != "object" {
+	if result == secret || result.GetName() != "" || result.Data != nil {
 		t.Fail()
 	}
-	result = GetValueOf(obj, "StructObject")
-	if result.Kind() != reflect.Struct {
+}
+
+func TestAddSupportedProvider(t *testing.T) {
+	AddSupportedProvider("b")
+	AddSupportedProvider("a")
+	AddSupportedProvider("b")
+	if !reflect.DeepEqual(GetSupportedProviders(), []string{"a", "b"}) {
 		t.Fail()
 	}
-	s := result.Interface().(TestSubObject)
-	if s.SubObject != "struct" {
-		t.Fail()
+	GetSupportedProviders()[0] = "c"
+	if !reflect.DeepEqual(GetSupportedProviders(), []string{"a", "b"}) {
+		t.Error("the registered providers changed through a returned slice")
 	}
-	result = GetValueOf(obj, "PointerObject")
-	if result.Kind() != reflect.Pointer {
+}
+
+func TestSetDeletionPolicy(t *testing.T) {
+	managed := &fake.Managed{}
+	SetDeletionPolicy(managed, xpv1.DeletionOrphan)
+	if managed.GetDeletionPolicy() != xpv1.DeletionOrphan {
 		t.Fail()
 	}
-	p := result.Interface().(*TestSubObject)
-	if p.SubObject != "pointer" {
+	SetDeletionPolicy(managed, "")
+	if managed.GetDeletionPolicy() != xpv1.DeletionOrphan {
 		t.Fail()
 	}
+	// Objects that are not managed resources are left untouched.
+	SetDeletionPolicy(&v1.Secret{}, xpv1.DeletionOrphan)
 }
 
-func TestSetValueOf(t *testing.T) {
-	obj := &TestObject{
-		"one", 1, []string{"one", "1"}, map[string]string{"one": "1"}, TestSubObject{"one"}, &TestSubObject{"one"},
-	}
-	obj2 := &TestObject{
-		"two", 2, []string{"two", "2"}, map[string]string{"two": "2"}, TestSubObject{"two"}, &TestSubObject{"two"},
-	}
+func TestMirrorLeastReady(t *testing.T) {
+	available, creating, failing := &fake.Managed{}, &fake.Managed{}, &fake.Managed{}
+	available.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+	creating.SetConditions(xpv1.Creating(), xpv1.ReconcileSuccess())
+	failing.SetConditions(xpv1.Available(), xpv1.ReconcileError(errors.New("quota exceeded")))
 
-	if obj.StringObject != "one" {
-		t.Fail()
-	}
-	_ = SetValueOf(obj, obj2, "StringObject")
-	if obj.StringObject != "two" {
-		t.Fail()
-	}
-	if obj.IntObject != 1 {
-		t.Fail()
+	status := &xpv1.ConditionedStatus{}
+	MirrorLeastReady(status, available, available)
+	if status.GetCondition(xpv1.TypeReady).Reason != xpv1.ReasonAvailable {
+		t.Error("expected resources that are all available to be ready")
 	}
-	_ = SetValueOf(obj, obj2, "IntObject")
-	if obj.IntObject != 2 {
-		t.Fail()
+	MirrorLeastReady(status, available, creating, failing)
+	if status.GetCondition(xpv1.TypeReady).Reason != xpv1.ReasonCreating {
+		t.Errorf("expected the creating resource to decide readiness, got %s", status.GetCondition(xpv1.TypeReady).Reason)
 	}
-	if obj.StructObject.SubObject != "one" {
-		t.Fail()
+	if status.GetCondition(xpv1.TypeSynced).Reason != xpv1.ReasonReconcileError {
+		t.Errorf("expected the failing resource to decide syncing, got %s", status.GetCondition(xpv1.TypeSynced).Reason)
 	}
-	_ = SetValueOf(obj, obj2, "StructObject")
-	if obj.StructObject.SubObject != "two" {
-		t.Fail()
+	MirrorLeastReady(status)
+	if status.GetCondition(xpv1.TypeReady).Reason != xpv1.ReasonCreating {
+		t.Error("expected no resources to be reported as creating")
 	}
-	if obj.PointerObject.SubObject != "one" {
-		t.Fail()
-	}
-	_ = SetValueOf(obj, obj2, "PointerObject")
-	if obj.PointerObject.SubObject != "two" {
-		t.Fail()
+}
+
+func TestConfirmCRD(t *testing.T) {
+	provider := schema.GroupVersionKind{Group: "pkg.crossplane.io", Version: "v1", Kind: "Provider"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(provider, meta.RESTScopeRoot)
+	if err := ConfirmCRD(mapper, provider); err != nil {
+		t.Error(err)
+	}
+	providerConfig := schema.GroupVersionKind{Group: "aws.upbound.io", Version: "v1beta1", Kind: "ProviderConfig"}
+	if err := ConfirmCRD(mapper, providerConfig); err == nil {
+		t.Error("expected missing kind to fail")
+	}
+	provider.Version = "v1beta1"
+	if err := ConfirmCRD(mapper, provider); err == nil {
+		t.Error("expected missing version to fail")
 	}
 }
@@ -0,0 +1,152 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	types "k8s.io/apimachinery/pkg/types"
+	field "k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	webhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	admission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+var virtualmachinelog = logf.Log.WithName("virtualmachine-resource")
+
+func SetupVirtualMachineWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&computev1alpha1.VirtualMachine{}).
+		WithDefaulter(&VirtualMachineCustomDefaulter{}).
+		WithValidator(&VirtualMachineCustomValidator{
+			MissionClient: clients.MissionClient{Client: mgr.GetClient()},
+		}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-compute-mission-control-apis-io-v1alpha1-virtualmachine,mutating=true,failurePolicy=fail,sideEffects=None,groups=compute.mission-control.apis.io,resources=virtualmachines,verbs=create;update,versions=v1alpha1,name=mvirtualmachine.kb.io,admissionReviewVersions=v1
+
+// VirtualMachineCustomDefaulter normalises the provider and fills in the
+// machine name and network when they are left out. Machines referencing a
+// Network do not get the default network.
+type VirtualMachineCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &VirtualMachineCustomDefaulter{}
+
+func (d *VirtualMachineCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	vm, ok := obj.(*computev1alpha1.VirtualMachine)
+	if !ok {
+		return fmt.Errorf("expected a VirtualMachine but got %T", obj)
+	}
+	virtualmachinelog.Info("default", "name", vm.Name)
+	vm.Spec.Provider = utils.NormalizeProvider(vm.Spec.Provider)
+	if vm.Spec.ForProvider.Name == "" {
+		vm.Spec.ForProvider.Name = vm.Name
+	}
+	if vm.Spec.ForProvider.NetworkRef != nil {
+		if vm.Spec.ForProvider.Network == computev1alpha1.DefaultNetwork {
+			vm.Spec.ForProvider.Network = ""
+		}
+	} else if vm.Spec.ForProvider.Network == "" {
+		vm.Spec.ForProvider.Network = computev1alpha1.DefaultNetwork
+	}
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-compute-mission-control-apis-io-v1alpha1-virtualmachine,mutating=false,failurePolicy=fail,sideEffects=None,groups=compute.mission-control.apis.io,resources=virtualmachines,verbs=create;update,versions=v1alpha1,name=vvirtualmachine.kb.io,admissionReviewVersions=v1
+
+// VirtualMachineCustomValidator rejects VirtualMachines that reference a Mission that does not
+// exist or whose name the selected provider would refuse, and those
+// referencing a Network of another Mission.
+type VirtualMachineCustomValidator struct {
+	clients.MissionClient
+}
+
+var _ webhook.CustomValidator = &VirtualMachineCustomValidator{}
+
+func (v *VirtualMachineCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	vm, ok := obj.(*computev1alpha1.VirtualMachine)
+	if !ok {
+		return nil, fmt.Errorf("expected a VirtualMachine but got %T", obj)
+	}
+	virtualmachinelog.Info("validate create", "name", vm.Name)
+	return nil, v.ValidateVirtualMachine(ctx, vm)
+}
+
+func (v *VirtualMachineCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	vm, ok := newObj.(*computev1alpha1.VirtualMachine)
+	if !ok {
+		return nil, fmt.Errorf("expected a VirtualMachine but got %T", newObj)
+	}
+	// Finalizers must be removable even if the Mission is already gone.
+	if !vm.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+	virtualmachinelog.Info("validate update", "name", vm.Name)
+	return nil, v.ValidateVirtualMachine(ctx, vm)
+}
+
+func (v *VirtualMachineCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *VirtualMachineCustomValidator) ValidateVirtualMachine(ctx context.Context, vm *computev1alpha1.VirtualMachine) error {
+	var allErrs field.ErrorList
+	refPath := field.NewPath("spec").Child("missionRef")
+	missionName := vm.Spec.MissionRef.MissionName
+	if missionName == "" {
+		allErrs = append(allErrs, field.Required(refPath.Child("missionName"), "a Mission is needed to create the resource"))
+	} else if mission, err := v.GetMission(ctx, missionName); apierrors.IsNotFound(err) {
+		allErrs = append(allErrs, field.NotFound(refPath.Child("missionName"), missionName))
+	} else if err != nil {
+		return apierrors.NewInternalError(err)
+	} else if pkg, err := mission.GetPackage(vm.Spec.Provider, vm.Spec.MissionRef.MissionKey); err != nil {
+		allErrs = append(allErrs, field.Invalid(refPath.Child("keyName"), vm.Spec.MissionRef.MissionKey, err.Error()))
+	} else if provider, err := providers.Get(pkg.Provider); err != nil {
+		allErrs = append(allErrs, field.Invalid(refPath.Child("missionName"), missionName, err.Error()))
+	} else if err := provider.VerifyVirtualMachine(vm); err != nil {
+		namePath := field.NewPath("spec").Child("forProvider").Child("name")
+		allErrs = append(allErrs, field.Invalid(namePath, vm.Spec.ForProvider.Name, err.Error()))
+	}
+	if ref := vm.Spec.ForProvider.NetworkRef; ref != nil && ref.Name != "" {
+		refPath := field.NewPath("spec").Child("forProvider").Child("networkRef")
+		network := &networkv1alpha1.Network{}
+		if err := v.Get(ctx, types.NamespacedName{Name: ref.Name}, network); apierrors.IsNotFound(err) {
+			allErrs = append(allErrs, field.NotFound(refPath.Child("name"), ref.Name))
+		} else if err != nil {
+			return apierrors.NewInternalError(err)
+		} else if network.Spec.MissionRef.MissionName != missionName {
+			allErrs = append(allErrs, field.Invalid(refPath.Child("name"), ref.Name, "the Network belongs to another Mission"))
+		} else if _, err := network.GetSubnet(ref.Subnet); err != nil {
+			allErrs = append(allErrs, field.Invalid(refPath.Child("subnet"), ref.Subnet, err.Error()))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(computev1alpha1.GroupVersion.WithKind("VirtualMachine").GroupKind(), vm.Name, allErrs)
+}
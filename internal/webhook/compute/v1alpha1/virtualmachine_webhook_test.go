@@ -0,0 +1,142 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	computev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/compute/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+)
+
+func newValidator(t *testing.T) *VirtualMachineCustomValidator {
+	scheme := runtime.NewScheme()
+	if err := missionv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := networkv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	mission := &missionv1alpha1.Mission{
+		ObjectMeta: metav1.ObjectMeta{Name: "mission"},
+		Spec: missionv1alpha1.MissionSpec{Packages: []missionv1alpha1.PackageConfig{{
+			Provider:    "gcp",
+			ProjectID:   "project",
+			Credentials: missionv1alpha1.CredentialConfig{Name: "gcp-key"},
+		}}},
+	}
+	network := &networkv1alpha1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: "network"},
+		Spec: networkv1alpha1.NetworkSpec{
+			MissionRef: networkv1alpha1.NetworkMissionRef{MissionName: "mission"},
+			ForProvider: networkv1alpha1.ProviderData{
+				Name:    "network",
+				Subnets: []networkv1alpha1.Subnet{{Name: "web", CIDR: "10.0.1.0/24", Region: "us-central1"}},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mission, network).Build()
+	return &VirtualMachineCustomValidator{MissionClient: clients.MissionClient{Client: c}}
+}
+
+func newVirtualMachine(missionName, name string) *computev1alpha1.VirtualMachine {
+	return &computev1alpha1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "vm"},
+		Spec: computev1alpha1.VirtualMachineSpec{
+			MissionRef:  computev1alpha1.VirtualMachineMissionRef{MissionName: missionName, MissionKey: "gcp-key"},
+			ForProvider: computev1alpha1.ProviderData{Name: name},
+		},
+	}
+}
+
+func withNetworkRef(vm *computev1alpha1.VirtualMachine, network, subnet string) *computev1alpha1.VirtualMachine {
+	vm.Spec.ForProvider.NetworkRef = &computev1alpha1.NetworkReference{Name: network, Subnet: subnet}
+	return vm
+}
+
+func TestValidateVirtualMachine(t *testing.T) {
+	namedAndReferenced := withNetworkRef(newVirtualMachine("mission", "samplevm"), "network", "")
+	namedAndReferenced.Spec.ForProvider.Network = "default"
+	cases := map[string]struct {
+		vm    *computev1alpha1.VirtualMachine
+		valid bool
+	}{
+		"valid":           {newVirtualMachine("mission", "samplevm"), true},
+		"missing mission": {newVirtualMachine("", "samplevm"), false},
+		"unknown mission": {newVirtualMachine("other", "samplevm"), false},
+		"invalid name":    {newVirtualMachine("mission", "1-sample_vm"), false},
+		"network ref":     {withNetworkRef(newVirtualMachine("mission", "samplevm"), "network", "web"), true},
+		"unknown network": {withNetworkRef(newVirtualMachine("mission", "samplevm"), "other", ""), false},
+		"unknown subnet":  {withNetworkRef(newVirtualMachine("mission", "samplevm"), "network", "db"), false},
+		"both networks":   {namedAndReferenced, false},
+	}
+	validator := newValidator(t)
+	for name, c := range cases {
+		_, err := validator.ValidateCreate(context.Background(), c.vm)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestValidateDeletingVirtualMachine(t *testing.T) {
+	vm := newVirtualMachine("other", "samplevm")
+	now := metav1.Now()
+	vm.SetDeletionTimestamp(&now)
+	if _, err := newValidator(t).ValidateUpdate(context.Background(), vm, vm); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDefaultVirtualMachine(t *testing.T) {
+	vm := newVirtualMachine("mission", "")
+	vm.Spec.Provider = "GCP"
+	if err := (&VirtualMachineCustomDefaulter{}).Default(context.Background(), vm); err != nil {
+		t.Fatal(err)
+	}
+	data := vm.Spec.ForProvider
+	if vm.Spec.Provider != "gcp" || data.Name != "vm" || data.Network != "default" {
+		t.Errorf("unexpected defaults %+v", vm.Spec)
+	}
+	if _, err := newValidator(t).ValidateCreate(context.Background(), vm); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDefaultVirtualMachineWithNetworkRef(t *testing.T) {
+	vm := withNetworkRef(newVirtualMachine("mission", "samplevm"), "network", "")
+	vm.Spec.ForProvider.Network = "default"
+	if err := (&VirtualMachineCustomDefaulter{}).Default(context.Background(), vm); err != nil {
+		t.Fatal(err)
+	}
+	if vm.Spec.ForProvider.Network != "" {
+		t.Errorf("expected the default network to be dropped, got %q", vm.Spec.ForProvider.Network)
+	}
+	if _, err := newValidator(t).ValidateCreate(context.Background(), vm); err != nil {
+		t.Error(err)
+	}
+}
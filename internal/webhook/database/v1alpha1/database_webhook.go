@@ -0,0 +1,171 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	field "k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	webhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	admission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+var databaselog = logf.Log.WithName("database-resource")
+
+func SetupDatabaseWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&databasev1alpha1.Database{}).
+		WithDefaulter(&DatabaseCustomDefaulter{}).
+		WithValidator(&DatabaseCustomValidator{
+			MissionClient: clients.MissionClient{Client: mgr.GetClient()},
+		}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-database-mission-control-apis-io-v1alpha1-database,mutating=true,failurePolicy=fail,sideEffects=None,groups=database.mission-control.apis.io,resources=databases,verbs=create;update,versions=v1alpha1,name=mdatabase.kb.io,admissionReviewVersions=v1
+
+// DatabaseCustomDefaulter normalises the provider, names the database after
+// the resource when no name is given and fills in the engine, version, size,
+// storage and username of the defaults.
+type DatabaseCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &DatabaseCustomDefaulter{}
+
+func (d *DatabaseCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	database, ok := obj.(*databasev1alpha1.Database)
+	if !ok {
+		return fmt.Errorf("expected a Database but got %T", obj)
+	}
+	databaselog.Info("default", "name", database.Name)
+	database.Spec.Provider = utils.NormalizeProvider(database.Spec.Provider)
+	data := &database.Spec.ForProvider
+	if data.Name == "" {
+		data.Name = database.Name
+	}
+	if data.Engine == "" {
+		data.Engine = databasev1alpha1.EnginePostgres
+	}
+	if data.Version == "" {
+		data.Version = databasev1alpha1.DefaultVersions[data.Engine]
+	}
+	if data.Size == "" {
+		data.Size = databasev1alpha1.DefaultSize
+	}
+	if data.StorageGB == 0 {
+		data.StorageGB = databasev1alpha1.DefaultStorageGB
+	}
+	if data.Username == "" {
+		data.Username = databasev1alpha1.DefaultUsername
+	}
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-database-mission-control-apis-io-v1alpha1-database,mutating=false,failurePolicy=fail,sideEffects=None,groups=database.mission-control.apis.io,resources=databases,verbs=create;update,versions=v1alpha1,name=vdatabase.kb.io,admissionReviewVersions=v1
+
+// DatabaseCustomValidator rejects Databases that reference a Mission that does
+// not exist or whose engine and sizes the selected provider does not offer,
+// as well as updates of the fields that identify the database.
+type DatabaseCustomValidator struct {
+	clients.MissionClient
+}
+
+var _ webhook.CustomValidator = &DatabaseCustomValidator{}
+
+func (v *DatabaseCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	database, ok := obj.(*databasev1alpha1.Database)
+	if !ok {
+		return nil, fmt.Errorf("expected a Database but got %T", obj)
+	}
+	databaselog.Info("validate create", "name", database.Name)
+	return nil, v.ValidateDatabase(ctx, database)
+}
+
+func (v *DatabaseCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	database, ok := newObj.(*databasev1alpha1.Database)
+	if !ok {
+		return nil, fmt.Errorf("expected a Database but got %T", newObj)
+	}
+	// Finalizers must be removable even if the Mission is already gone.
+	if !database.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+	oldDatabase, ok := oldObj.(*databasev1alpha1.Database)
+	if !ok {
+		return nil, fmt.Errorf("expected a Database but got %T", oldObj)
+	}
+	databaselog.Info("validate update", "name", database.Name)
+	if allErrs := ValidateImmutable(oldDatabase, database); len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(databasev1alpha1.GroupVersion.WithKind("Database").GroupKind(), database.Name, allErrs)
+	}
+	return nil, v.ValidateDatabase(ctx, database)
+}
+
+// ValidateImmutable rejects changes to the fields that cannot be applied to
+// an existing database. Providers replace the database when its name, region
+// or engine changes, the administrator account is only created once and the
+// connection Secret is not moved.
+func ValidateImmutable(oldDatabase, database *databasev1alpha1.Database) field.ErrorList {
+	specPath := field.NewPath("spec")
+	dataPath := specPath.Child("forProvider")
+	oldData, data := oldDatabase.Spec.ForProvider, database.Spec.ForProvider
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(data.Name, oldData.Name, dataPath.Child("name"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(data.Region, oldData.Region, dataPath.Child("region"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(data.Engine, oldData.Engine, dataPath.Child("engine"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(data.Username, oldData.Username, dataPath.Child("username"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(database.Spec.ConnectionSecretRef, oldDatabase.Spec.ConnectionSecretRef, specPath.Child("connectionSecretRef"))...)
+	return allErrs
+}
+
+func (v *DatabaseCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *DatabaseCustomValidator) ValidateDatabase(ctx context.Context, database *databasev1alpha1.Database) error {
+	var allErrs field.ErrorList
+	refPath := field.NewPath("spec").Child("missionRef")
+	missionName := database.Spec.MissionRef.MissionName
+	if missionName == "" {
+		allErrs = append(allErrs, field.Required(refPath.Child("missionName"), "a Mission is needed to create the resource"))
+	} else if mission, err := v.GetMission(ctx, missionName); apierrors.IsNotFound(err) {
+		allErrs = append(allErrs, field.NotFound(refPath.Child("missionName"), missionName))
+	} else if err != nil {
+		return apierrors.NewInternalError(err)
+	} else if pkg, err := mission.GetPackage(database.Spec.Provider, database.Spec.MissionRef.MissionKey); err != nil {
+		allErrs = append(allErrs, field.Invalid(refPath.Child("keyName"), database.Spec.MissionRef.MissionKey, err.Error()))
+	} else if provider, err := providers.Get(pkg.Provider); err != nil {
+		allErrs = append(allErrs, field.Invalid(refPath.Child("missionName"), missionName, err.Error()))
+	} else if err := provider.VerifyDatabase(database); err != nil {
+		dataPath := field.NewPath("spec").Child("forProvider")
+		allErrs = append(allErrs, field.Invalid(dataPath, database.Spec.ForProvider.Name, err.Error()))
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(databasev1alpha1.GroupVersion.WithKind("Database").GroupKind(), database.Name, allErrs)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	databasev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/database/v1alpha1"
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+)
+
+func newValidator(t *testing.T) *DatabaseCustomValidator {
+	scheme := runtime.NewScheme()
+	if err := missionv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	var missions []runtime.Object
+	for _, provider := range []string{"gcp", "aws", "azure"} {
+		missions = append(missions, &missionv1alpha1.Mission{
+			ObjectMeta: metav1.ObjectMeta{Name: "mission-" + provider},
+			Spec: missionv1alpha1.MissionSpec{Packages: []missionv1alpha1.PackageConfig{{
+				Provider:    provider,
+				ProjectID:   "project",
+				Credentials: missionv1alpha1.CredentialConfig{Name: provider + "-key"},
+			}}},
+		})
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(missions...).Build()
+	return &DatabaseCustomValidator{MissionClient: clients.MissionClient{Client: c}}
+}
+
+func newDatabase(provider string) *databasev1alpha1.Database {
+	return &databasev1alpha1.Database{
+		ObjectMeta: metav1.ObjectMeta{Name: "database"},
+		Spec: databasev1alpha1.DatabaseSpec{
+			MissionRef: databasev1alpha1.DatabaseMissionRef{MissionName: "mission-" + provider, MissionKey: provider + "-key"},
+			ForProvider: databasev1alpha1.ProviderData{
+				Name:      "database",
+				Region:    "us-east-1",
+				Engine:    "postgres",
+				Version:   "15",
+				Size:      "medium",
+				StorageGB: 32,
+				Username:  "app",
+			},
+			ConnectionSecretRef: xpv1.SecretReference{Name: "database-connection", Namespace: "default"},
+		},
+	}
+}
+
+func TestValidateDatabase(t *testing.T) {
+	missing := newDatabase("gcp")
+	missing.Spec.MissionRef.MissionName = ""
+	gcpMySQL := newDatabase("gcp")
+	gcpMySQL.Spec.ForProvider.Engine = "mysql"
+	gcpMySQL.Spec.ForProvider.Version = "8.0"
+	gcpMySQLMajor := newDatabase("gcp")
+	gcpMySQLMajor.Spec.ForProvider.Engine = "mysql"
+	gcpMySQLMajor.Spec.ForProvider.Version = "8"
+	awsSmallStorage := newDatabase("aws")
+	awsSmallStorage.Spec.ForProvider.StorageGB = 10
+	awsUppercaseName := newDatabase("aws")
+	awsUppercaseName.Spec.ForProvider.Name = "Database"
+	azureMySQL := newDatabase("azure")
+	azureMySQL.Spec.ForProvider.Engine = "mysql"
+	azureStorage := newDatabase("azure")
+	azureStorage.Spec.ForProvider.StorageGB = 50
+	azureSmallHA := newDatabase("azure")
+	azureSmallHA.Spec.ForProvider.Size = "small"
+	azureSmallHA.Spec.ForProvider.HighAvailability = true
+	unknownSize := newDatabase("aws")
+	unknownSize.Spec.ForProvider.Size = "huge"
+	invalidVersion := newDatabase("aws")
+	invalidVersion.Spec.ForProvider.Version = "latest"
+	reservedUser := newDatabase("gcp")
+	reservedUser.Spec.ForProvider.Username = "postgres"
+	longMySQLUser := newDatabase("aws")
+	longMySQLUser.Spec.ForProvider.Engine = "mysql"
+	longMySQLUser.Spec.ForProvider.Version = "8.0"
+	longMySQLUser.Spec.ForProvider.Username = "application_owner"
+	missingSecret := newDatabase("gcp")
+	missingSecret.Spec.ConnectionSecretRef.Namespace = ""
+
+	cases := map[string]struct {
+		database *databasev1alpha1.Database
+		valid    bool
+	}{
+		"valid gcp":           {newDatabase("gcp"), true},
+		"valid aws":           {newDatabase("aws"), true},
+		"valid azure":         {newDatabase("azure"), true},
+		"missing mission":     {missing, false},
+		"gcp mysql":           {gcpMySQL, true},
+		"gcp mysql major":     {gcpMySQLMajor, false},
+		"aws small storage":   {awsSmallStorage, false},
+		"aws uppercase name":  {awsUppercaseName, false},
+		"azure mysql":         {azureMySQL, false},
+		"azure storage size":  {azureStorage, false},
+		"azure small ha":      {azureSmallHA, false},
+		"unknown size":        {unknownSize, false},
+		"invalid version":     {invalidVersion, false},
+		"reserved username":   {reservedUser, false},
+		"long mysql username": {longMySQLUser, false},
+		"missing secret":      {missingSecret, false},
+	}
+	validator := newValidator(t)
+	for name, c := range cases {
+		_, err := validator.ValidateCreate(context.Background(), c.database)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestValidateDeletingDatabase(t *testing.T) {
+	database := newDatabase("other")
+	now := metav1.Now()
+	database.SetDeletionTimestamp(&now)
+	if _, err := newValidator(t).ValidateUpdate(context.Background(), database, database); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDefaultDatabase(t *testing.T) {
+	database := newDatabase("gcp")
+	database.Spec.ForProvider = databasev1alpha1.ProviderData{Engine: "mysql"}
+	database.Spec.Provider = "GCP"
+	if err := (&DatabaseCustomDefaulter{}).Default(context.Background(), database); err != nil {
+		t.Fatal(err)
+	}
+	expected := databasev1alpha1.ProviderData{
+		Name:      "database",
+		Engine:    "mysql",
+		Version:   "8.0",
+		Size:      "small",
+		StorageGB: 20,
+		Username:  "missioncontrol",
+	}
+	if database.Spec.Provider != "gcp" || database.Spec.ForProvider != expected {
+		t.Errorf("unexpected defaults %+v", database.Spec)
+	}
+}
+
+func TestValidateDatabaseUpdate(t *testing.T) {
+	resized := newDatabase("gcp")
+	resized.Spec.ForProvider.Size = "large"
+	resized.Spec.ForProvider.StorageGB = 64
+	renamed := newDatabase("gcp")
+	renamed.Spec.ForProvider.Name = "other"
+	moved := newDatabase("gcp")
+	moved.Spec.ForProvider.Region = "europe-west1"
+	mysql := newDatabase("gcp")
+	mysql.Spec.ForProvider.Engine = "mysql"
+	mysql.Spec.ForProvider.Version = "8.0"
+	otherUser := newDatabase("gcp")
+	otherUser.Spec.ForProvider.Username = "admin"
+	otherSecret := newDatabase("gcp")
+	otherSecret.Spec.ConnectionSecretRef.Namespace = "apps"
+
+	cases := map[string]struct {
+		database *databasev1alpha1.Database
+		valid    bool
+	}{
+		"resized":      {resized, true},
+		"renamed":      {renamed, false},
+		"moved":        {moved, false},
+		"other engine": {mysql, false},
+		"other user":   {otherUser, false},
+		"other secret": {otherSecret, false},
+	}
+	validator := newValidator(t)
+	for name, c := range cases {
+		_, err := validator.ValidateUpdate(context.Background(), newDatabase("gcp"), c.database)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	field "k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	webhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	admission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+var missionlog = logf.Log.WithName("mission-resource")
+
+func SetupMissionWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&missionv1alpha1.Mission{}).
+		WithDefaulter(&MissionCustomDefaulter{}).
+		WithValidator(&MissionCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-mission-mission-control-apis-io-v1alpha1-mission,mutating=true,failurePolicy=fail,sideEffects=None,groups=mission.mission-control.apis.io,resources=missions,verbs=create;update,versions=v1alpha1,name=mmission.kb.io,admissionReviewVersions=v1
+
+// MissionCustomDefaulter normalises provider names and fills in where the
+// credentials of each package are read from.
+type MissionCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &MissionCustomDefaulter{}
+
+func (d *MissionCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	mission, ok := obj.(*missionv1alpha1.Mission)
+	if !ok {
+		return fmt.Errorf("expected a Mission but got %T", obj)
+	}
+	missionlog.Info("default", "name", mission.Name)
+	for i := range mission.Spec.Packages {
+		pkg := &mission.Spec.Packages[i]
+		pkg.Provider = utils.NormalizeProvider(pkg.Provider)
+		if pkg.Credentials.Key == "" {
+			pkg.Credentials.Key = missionv1alpha1.DefaultCredentialsKey
+		}
+		if pkg.Credentials.Namespace == "" {
+			pkg.Credentials.Namespace = missionv1alpha1.DefaultCredentialsNamespace
+		}
+	}
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-mission-mission-control-apis-io-v1alpha1-mission,mutating=false,failurePolicy=fail,sideEffects=None,groups=mission.mission-control.apis.io,resources=missions,verbs=create;update,versions=v1alpha1,name=vmission.kb.io,admissionReviewVersions=v1
+
+// MissionCustomValidator rejects Missions whose packages the controllers
+// would not be able to reconcile.
+type MissionCustomValidator struct{}
+
+var _ webhook.CustomValidator = &MissionCustomValidator{}
+
+func (v *MissionCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	mission, ok := obj.(*missionv1alpha1.Mission)
+	if !ok {
+		return nil, fmt.Errorf("expected a Mission but got %T", obj)
+	}
+	missionlog.Info("validate create", "name", mission.Name)
+	return nil, ValidateMission(mission)
+}
+
+func (v *MissionCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	mission, ok := newObj.(*missionv1alpha1.Mission)
+	if !ok {
+		return nil, fmt.Errorf("expected a Mission but got %T", newObj)
+	}
+	// Finalizers must be removable even if a provider is no longer known.
+	if !mission.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+	missionlog.Info("validate update", "name", mission.Name)
+	return nil, ValidateMission(mission)
+}
+
+func (v *MissionCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateMission runs the Verify method of each package provider and checks
+// that no provider is used twice, as they would share a ProviderConfig.
+func ValidateMission(mission *missionv1alpha1.Mission) error {
+	var allErrs field.ErrorList
+	packagesPath := field.NewPath("spec").Child("packages")
+	seen := map[string]bool{}
+	for i, pkg := range mission.Spec.Packages {
+		providerPath := packagesPath.Index(i).Child("provider")
+		provider, err := providers.Get(pkg.Provider)
+		if err != nil {
+			allErrs = append(allErrs, field.NotSupported(providerPath, pkg.Provider, utils.GetSupportedProviders()))
+			continue
+		}
+		if seen[provider.Name()] {
+			allErrs = append(allErrs, field.Duplicate(providerPath, pkg.Provider))
+		}
+		seen[provider.Name()] = true
+		if err := provider.VerifyMission(mission, i); err != nil {
+			allErrs = append(allErrs, field.Invalid(packagesPath.Index(i), provider.Name(), err.Error()))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(missionv1alpha1.GroupVersion.WithKind("Mission").GroupKind(), mission.Name, allErrs)
+}
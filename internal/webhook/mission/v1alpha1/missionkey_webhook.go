@@ -0,0 +1,126 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	field "k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	webhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	admission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	encryption "github.com/holy-tech/Mission-Control-Operator/internal/controller/encryption"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+var missionkeylog = logf.Log.WithName("missionkey-resource")
+
+func SetupMissionKeyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&missionv1alpha1.MissionKey{}).
+		WithDefaulter(&MissionKeyCustomDefaulter{}).
+		WithValidator(&MissionKeyCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-mission-mission-control-apis-io-v1alpha1-missionkey,mutating=true,failurePolicy=fail,sideEffects=None,groups=mission.mission-control.apis.io,resources=missionkeys,verbs=create;update,versions=v1alpha1,name=mmissionkey.kb.io,admissionReviewVersions=v1
+
+// MissionKeyCustomDefaulter normalises the provider type of MissionKeys and
+// places referenced Secrets in the namespace of the key.
+type MissionKeyCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &MissionKeyCustomDefaulter{}
+
+func (d *MissionKeyCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	key, ok := obj.(*missionv1alpha1.MissionKey)
+	if !ok {
+		return fmt.Errorf("expected a MissionKey but got %T", obj)
+	}
+	missionkeylog.Info("default", "name", key.Name, "namespace", key.Namespace)
+	key.Spec.Type = utils.NormalizeProvider(key.Spec.Type)
+	if key.Spec.SecretRef != nil && key.Spec.SecretRef.Namespace == "" {
+		key.Spec.SecretRef.Namespace = key.Namespace
+	}
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-mission-mission-control-apis-io-v1alpha1-missionkey,mutating=false,failurePolicy=fail,sideEffects=None,groups=mission.mission-control.apis.io,resources=missionkeys,verbs=create;update,versions=v1alpha1,name=vmissionkey.kb.io,admissionReviewVersions=v1
+
+// MissionKeyCustomValidator rejects MissionKeys for unknown providers or with
+// credentials or identities their provider does not accept.
+type MissionKeyCustomValidator struct{}
+
+var _ webhook.CustomValidator = &MissionKeyCustomValidator{}
+
+func (v *MissionKeyCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	key, ok := obj.(*missionv1alpha1.MissionKey)
+	if !ok {
+		return nil, fmt.Errorf("expected a MissionKey but got %T", obj)
+	}
+	missionkeylog.Info("validate create", "name", key.Name, "namespace", key.Namespace)
+	return nil, ValidateMissionKey(key)
+}
+
+func (v *MissionKeyCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	key, ok := newObj.(*missionv1alpha1.MissionKey)
+	if !ok {
+		return nil, fmt.Errorf("expected a MissionKey but got %T", newObj)
+	}
+	// Finalizers must be removable even if the encryption provider is gone.
+	if !key.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+	missionkeylog.Info("validate update", "name", key.Name, "namespace", key.Namespace)
+	return nil, ValidateMissionKey(key)
+}
+
+func (v *MissionKeyCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func ValidateMissionKey(key *missionv1alpha1.MissionKey) error {
+	var allErrs field.ErrorList
+	typePath := field.NewPath("spec").Child("type")
+	provider, err := providers.Get(key.Spec.Type)
+	if err != nil {
+		allErrs = append(allErrs, field.NotSupported(typePath, key.Spec.Type, utils.GetSupportedProviders()))
+	} else if err := provider.VerifyKey(key); err != nil {
+		if key.UsesIdentity() {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("identity"), key.Spec.Identity.ID, err.Error()))
+		} else if key.Spec.Source != "" {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("source"), key.Spec.Source, err.Error()))
+		} else {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("data"), "", err.Error()))
+		}
+	}
+	if key.Spec.Encryption != nil {
+		if _, err := encryption.Get(key.Spec.Encryption.Provider); err != nil {
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("spec").Child("encryption").Child("provider"), key.Spec.Encryption.Provider, encryption.Names()))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(missionv1alpha1.GroupVersion.WithKind("MissionKey").GroupKind(), key.Name, allErrs)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	field "k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	webhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	admission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+	utils "github.com/holy-tech/Mission-Control-Operator/internal/controller/utils"
+)
+
+var networklog = logf.Log.WithName("network-resource")
+
+func SetupNetworkWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&networkv1alpha1.Network{}).
+		WithDefaulter(&NetworkCustomDefaulter{}).
+		WithValidator(&NetworkCustomValidator{
+			MissionClient: clients.MissionClient{Client: mgr.GetClient()},
+		}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-network-mission-control-apis-io-v1alpha1-network,mutating=true,failurePolicy=fail,sideEffects=None,groups=network.mission-control.apis.io,resources=networks,verbs=create;update,versions=v1alpha1,name=mnetwork.kb.io,admissionReviewVersions=v1
+
+// NetworkCustomDefaulter normalises the provider and names the network after
+// the resource when no name is given.
+type NetworkCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &NetworkCustomDefaulter{}
+
+func (d *NetworkCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	network, ok := obj.(*networkv1alpha1.Network)
+	if !ok {
+		return fmt.Errorf("expected a Network but got %T", obj)
+	}
+	networklog.Info("default", "name", network.Name)
+	network.Spec.Provider = utils.NormalizeProvider(network.Spec.Provider)
+	if network.Spec.ForProvider.Name == "" {
+		network.Spec.ForProvider.Name = network.Name
+	}
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-network-mission-control-apis-io-v1alpha1-network,mutating=false,failurePolicy=fail,sideEffects=None,groups=network.mission-control.apis.io,resources=networks,verbs=create;update,versions=v1alpha1,name=vnetwork.kb.io,admissionReviewVersions=v1
+
+// NetworkCustomValidator rejects Networks that reference a Mission that does
+// not exist or whose ranges and rules the selected provider would refuse.
+type NetworkCustomValidator struct {
+	clients.MissionClient
+}
+
+var _ webhook.CustomValidator = &NetworkCustomValidator{}
+
+func (v *NetworkCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	network, ok := obj.(*networkv1alpha1.Network)
+	if !ok {
+		return nil, fmt.Errorf("expected a Network but got %T", obj)
+	}
+	networklog.Info("validate create", "name", network.Name)
+	return nil, v.ValidateNetwork(ctx, network)
+}
+
+func (v *NetworkCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	network, ok := newObj.(*networkv1alpha1.Network)
+	if !ok {
+		return nil, fmt.Errorf("expected a Network but got %T", newObj)
+	}
+	// Finalizers must be removable even if the Mission is already gone.
+	if !network.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+	oldNetwork, ok := oldObj.(*networkv1alpha1.Network)
+	if !ok {
+		return nil, fmt.Errorf("expected a Network but got %T", oldObj)
+	}
+	networklog.Info("validate update", "name", network.Name)
+	if allErrs := ValidateImmutable(oldNetwork, network); len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(networkv1alpha1.GroupVersion.WithKind("Network").GroupKind(), network.Name, allErrs)
+	}
+	return nil, v.ValidateNetwork(ctx, network)
+}
+
+// ValidateImmutable rejects changes to the fields that cannot be applied to
+// an existing network. Providers replace the network when its name, region
+// or address range changes, together with every subnet and rule in it.
+func ValidateImmutable(oldNetwork, network *networkv1alpha1.Network) field.ErrorList {
+	dataPath := field.NewPath("spec").Child("forProvider")
+	oldData, data := oldNetwork.Spec.ForProvider, network.Spec.ForProvider
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(data.Name, oldData.Name, dataPath.Child("name"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(data.Region, oldData.Region, dataPath.Child("region"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(data.CIDR, oldData.CIDR, dataPath.Child("cidr"))...)
+	return allErrs
+}
+
+func (v *NetworkCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *NetworkCustomValidator) ValidateNetwork(ctx context.Context, network *networkv1alpha1.Network) error {
+	var allErrs field.ErrorList
+	refPath := field.NewPath("spec").Child("missionRef")
+	missionName := network.Spec.MissionRef.MissionName
+	if missionName == "" {
+		allErrs = append(allErrs, field.Required(refPath.Child("missionName"), "a Mission is needed to create the resource"))
+	} else if mission, err := v.GetMission(ctx, missionName); apierrors.IsNotFound(err) {
+		allErrs = append(allErrs, field.NotFound(refPath.Child("missionName"), missionName))
+	} else if err != nil {
+		return apierrors.NewInternalError(err)
+	} else if pkg, err := mission.GetPackage(network.Spec.Provider, network.Spec.MissionRef.MissionKey); err != nil {
+		allErrs = append(allErrs, field.Invalid(refPath.Child("keyName"), network.Spec.MissionRef.MissionKey, err.Error()))
+	} else if provider, err := providers.Get(pkg.Provider); err != nil {
+		allErrs = append(allErrs, field.Invalid(refPath.Child("missionName"), missionName, err.Error()))
+	} else if err := provider.VerifyNetwork(network); err != nil {
+		dataPath := field.NewPath("spec").Child("forProvider")
+		allErrs = append(allErrs, field.Invalid(dataPath, network.Spec.ForProvider.Name, err.Error()))
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(networkv1alpha1.GroupVersion.WithKind("Network").GroupKind(), network.Name, allErrs)
+}
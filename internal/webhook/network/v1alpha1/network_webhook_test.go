@@ -0,0 +1,195 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	networkv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/network/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+)
+
+func newValidator(t *testing.T) *NetworkCustomValidator {
+	scheme := runtime.NewScheme()
+	if err := missionv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	var missions []runtime.Object
+	for _, provider := range []string{"gcp", "aws", "azure"} {
+		missions = append(missions, &missionv1alpha1.Mission{
+			ObjectMeta: metav1.ObjectMeta{Name: "mission-" + provider},
+			Spec: missionv1alpha1.MissionSpec{Packages: []missionv1alpha1.PackageConfig{{
+				Provider:    provider,
+				ProjectID:   "project",
+				Credentials: missionv1alpha1.CredentialConfig{Name: provider + "-key"},
+			}}},
+		})
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(missions...).Build()
+	return &NetworkCustomValidator{MissionClient: clients.MissionClient{Client: c}}
+}
+
+func newNetwork(provider string) *networkv1alpha1.Network {
+	return &networkv1alpha1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: "network"},
+		Spec: networkv1alpha1.NetworkSpec{
+			MissionRef: networkv1alpha1.NetworkMissionRef{MissionName: "mission-" + provider, MissionKey: provider + "-key"},
+			ForProvider: networkv1alpha1.ProviderData{
+				Name:   "network",
+				Region: "us-east-1",
+				CIDR:   "10.0.0.0/16",
+				Subnets: []networkv1alpha1.Subnet{
+					{Name: "web", CIDR: "10.0.1.0/24"},
+				},
+				FirewallRules: []networkv1alpha1.FirewallRule{
+					{Name: "ssh", Protocol: "tcp", Ports: []string{"22"}, SourceRanges: []string{"10.0.0.0/8"}},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateNetwork(t *testing.T) {
+	missing := newNetwork("gcp")
+	missing.Spec.MissionRef.MissionName = ""
+	gcpRegionless := newNetwork("gcp")
+	gcpRegionless.Spec.ForProvider.Region = ""
+	gcpWithSubnetRegion := newNetwork("gcp")
+	gcpWithSubnetRegion.Spec.ForProvider.Region = ""
+	gcpWithSubnetRegion.Spec.ForProvider.CIDR = ""
+	gcpWithSubnetRegion.Spec.ForProvider.Subnets[0].Region = "us-central1"
+	awsWithoutCIDR := newNetwork("aws")
+	awsWithoutCIDR.Spec.ForProvider.CIDR = ""
+	awsOtherZone := newNetwork("aws")
+	awsOtherZone.Spec.ForProvider.Subnets[0].Region = "eu-west-1a"
+	outsideCIDR := newNetwork("azure")
+	outsideCIDR.Spec.ForProvider.Subnets[0].CIDR = "192.168.0.0/24"
+	widerThanNetwork := newNetwork("aws")
+	widerThanNetwork.Spec.ForProvider.Subnets[0].CIDR = "10.0.0.0/8"
+	overlappingSubnet := newNetwork("gcp")
+	overlappingSubnet.Spec.ForProvider.Subnets = append(overlappingSubnet.Spec.ForProvider.Subnets, networkv1alpha1.Subnet{Name: "db", CIDR: "10.0.0.0/20"})
+	duplicateSubnet := newNetwork("azure")
+	duplicateSubnet.Spec.ForProvider.Subnets = append(duplicateSubnet.Spec.ForProvider.Subnets, networkv1alpha1.Subnet{Name: "web", CIDR: "10.0.2.0/24"})
+	icmpPorts := newNetwork("azure")
+	icmpPorts.Spec.ForProvider.FirewallRules[0].Protocol = "icmp"
+	reversedPorts := newNetwork("aws")
+	reversedPorts.Spec.ForProvider.FirewallRules[0].Ports = []string{"8080-8000"}
+	awsRuleCollision := newNetwork("aws")
+	awsRuleCollision.Spec.ForProvider.FirewallRules = []networkv1alpha1.FirewallRule{
+		{Name: "web", Protocol: "tcp", Ports: []string{"80", "443"}},
+		{Name: "web-0", Protocol: "tcp", Ports: []string{"8080"}},
+	}
+	gcpNumberedRules := newNetwork("gcp")
+	gcpNumberedRules.Spec.ForProvider.FirewallRules = awsRuleCollision.Spec.ForProvider.FirewallRules
+	awsEgressCollision := newNetwork("aws")
+	awsEgressCollision.Spec.ForProvider.FirewallRules[0].Name = networkv1alpha1.AWSEgressRule
+	invalidSource := newNetwork("aws")
+	invalidSource.Spec.ForProvider.FirewallRules[0].SourceRanges = []string{"10.0.0.1"}
+
+	cases := map[string]struct {
+		network *networkv1alpha1.Network
+		valid   bool
+	}{
+		"valid gcp":                 {newNetwork("gcp"), true},
+		"valid aws":                 {newNetwork("aws"), true},
+		"valid azure":               {newNetwork("azure"), true},
+		"missing mission":           {missing, false},
+		"gcp subnet region":         {gcpRegionless, false},
+		"gcp without cidr":          {gcpWithSubnetRegion, true},
+		"aws without cidr":          {awsWithoutCIDR, false},
+		"aws other region":          {awsOtherZone, false},
+		"subnet outside network":    {outsideCIDR, false},
+		"subnet wider than network": {widerThanNetwork, false},
+		"overlapping subnets":       {overlappingSubnet, false},
+		"duplicate subnet":          {duplicateSubnet, false},
+		"icmp ports":                {icmpPorts, false},
+		"reversed ports":            {reversedPorts, false},
+		"aws rule collision":        {awsRuleCollision, false},
+		"gcp numbered rules":        {gcpNumberedRules, true},
+		"aws egress collision":      {awsEgressCollision, false},
+		"invalid source":            {invalidSource, false},
+	}
+	validator := newValidator(t)
+	for name, c := range cases {
+		_, err := validator.ValidateCreate(context.Background(), c.network)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestValidateDeletingNetwork(t *testing.T) {
+	network := newNetwork("other")
+	now := metav1.Now()
+	network.SetDeletionTimestamp(&now)
+	if _, err := newValidator(t).ValidateUpdate(context.Background(), network, network); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDefaultNetwork(t *testing.T) {
+	network := newNetwork("gcp")
+	network.Spec.ForProvider.Name = ""
+	network.Spec.Provider = "GCP"
+	if err := (&NetworkCustomDefaulter{}).Default(context.Background(), network); err != nil {
+		t.Fatal(err)
+	}
+	if network.Spec.Provider != "gcp" || network.Spec.ForProvider.Name != "network" {
+		t.Errorf("unexpected defaults %+v", network.Spec)
+	}
+}
+
+func TestValidateNetworkUpdate(t *testing.T) {
+	withRule := newNetwork("gcp")
+	withRule.Spec.ForProvider.FirewallRules = append(withRule.Spec.ForProvider.FirewallRules,
+		networkv1alpha1.FirewallRule{Name: "http", Protocol: "tcp", Ports: []string{"80"}})
+	renamed := newNetwork("gcp")
+	renamed.Spec.ForProvider.Name = "other"
+	moved := newNetwork("gcp")
+	moved.Spec.ForProvider.Region = "europe-west1"
+	resized := newNetwork("gcp")
+	resized.Spec.ForProvider.CIDR = "10.0.0.0/8"
+
+	cases := map[string]struct {
+		network *networkv1alpha1.Network
+		valid   bool
+	}{
+		"new rule": {withRule, true},
+		"renamed":  {renamed, false},
+		"moved":    {moved, false},
+		"resized":  {resized, false},
+	}
+	validator := newValidator(t)
+	for name, c := range cases {
+		_, err := validator.ValidateUpdate(context.Background(), newNetwork("gcp"), c.network)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	field "k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	webhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	admission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+	providers "github.com/holy-tech/Mission-Control-Operator/internal/controller/providers"
+)
+
+var storagebucketslog = logf.Log.WithName("storagebuckets-resource")
+
+func SetupStorageBucketsWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&storagev1alpha1.StorageBuckets{}).
+		WithDefaulter(&StorageBucketsCustomDefaulter{}).
+		WithValidator(&StorageBucketsCustomValidator{
+			MissionClient: clients.MissionClient{Client: mgr.GetClient()},
+		}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-storage-mission-control-apis-io-v1alpha1-storagebuckets,mutating=true,failurePolicy=fail,sideEffects=None,groups=storage.mission-control.apis.io,resources=storagebuckets,verbs=create;update,versions=v1alpha1,name=mstoragebuckets.kb.io,admissionReviewVersions=v1
+
+// StorageBucketsCustomDefaulter names the bucket after the resource when no
+// name is given.
+type StorageBucketsCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &StorageBucketsCustomDefaulter{}
+
+func (d *StorageBucketsCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	bucket, ok := obj.(*storagev1alpha1.StorageBuckets)
+	if !ok {
+		return fmt.Errorf("expected a StorageBuckets but got %T", obj)
+	}
+	storagebucketslog.Info("default", "name", bucket.Name)
+	if bucket.Spec.ForProvider.Name == "" {
+		bucket.Spec.ForProvider.Name = bucket.Name
+	}
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-storage-mission-control-apis-io-v1alpha1-storagebuckets,mutating=false,failurePolicy=fail,sideEffects=None,groups=storage.mission-control.apis.io,resources=storagebuckets,verbs=create;update,versions=v1alpha1,name=vstoragebuckets.kb.io,admissionReviewVersions=v1
+
+// StorageBucketsCustomValidator rejects StorageBuckets that reference a Mission that does not
+// exist or whose name the selected provider would refuse.
+type StorageBucketsCustomValidator struct {
+	clients.MissionClient
+}
+
+var _ webhook.CustomValidator = &StorageBucketsCustomValidator{}
+
+func (v *StorageBucketsCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	bucket, ok := obj.(*storagev1alpha1.StorageBuckets)
+	if !ok {
+		return nil, fmt.Errorf("expected a StorageBuckets but got %T", obj)
+	}
+	storagebucketslog.Info("validate create", "name", bucket.Name)
+	return nil, v.ValidateStorageBuckets(ctx, bucket)
+}
+
+func (v *StorageBucketsCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	bucket, ok := newObj.(*storagev1alpha1.StorageBuckets)
+	if !ok {
+		return nil, fmt.Errorf("expected a StorageBuckets but got %T", newObj)
+	}
+	// Finalizers must be removable even if the Mission is already gone.
+	if !bucket.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+	storagebucketslog.Info("validate update", "name", bucket.Name)
+	return nil, v.ValidateStorageBuckets(ctx, bucket)
+}
+
+func (v *StorageBucketsCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *StorageBucketsCustomValidator) ValidateStorageBuckets(ctx context.Context, bucket *storagev1alpha1.StorageBuckets) error {
+	var allErrs field.ErrorList
+	refPath := field.NewPath("spec").Child("missionRef")
+	missionName := bucket.Spec.MissionRef.MissionName
+	if missionName == "" {
+		allErrs = append(allErrs, field.Required(refPath.Child("missionName"), "a Mission is needed to create the resource"))
+	} else if mission, err := v.GetMission(ctx, missionName); apierrors.IsNotFound(err) {
+		allErrs = append(allErrs, field.NotFound(refPath.Child("missionName"), missionName))
+	} else if err != nil {
+		return apierrors.NewInternalError(err)
+	} else if pkg, err := mission.GetPackage("", bucket.Spec.MissionRef.MissionKey); err != nil {
+		allErrs = append(allErrs, field.Invalid(refPath.Child("keyName"), bucket.Spec.MissionRef.MissionKey, err.Error()))
+	} else if provider, err := providers.Get(pkg.Provider); err != nil {
+		allErrs = append(allErrs, field.Invalid(refPath.Child("missionName"), missionName, err.Error()))
+	} else if err := provider.VerifyStorageBucket(bucket); err != nil {
+		namePath := field.NewPath("spec").Child("forProvider").Child("name")
+		allErrs = append(allErrs, field.Invalid(namePath, bucket.Spec.ForProvider.Name, err.Error()))
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(storagev1alpha1.GroupVersion.WithKind("StorageBuckets").GroupKind(), bucket.Name, allErrs)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	missionv1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/mission/v1alpha1"
+	storagev1alpha1 "github.com/holy-tech/Mission-Control-Operator/api/storage/v1alpha1"
+	clients "github.com/holy-tech/Mission-Control-Operator/internal/controller/clients"
+)
+
+func TestValidateStorageBuckets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := missionv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	mission := &missionv1alpha1.Mission{
+		ObjectMeta: metav1.ObjectMeta{Name: "mission"},
+		Spec: missionv1alpha1.MissionSpec{Packages: []missionv1alpha1.PackageConfig{
+			{Provider: "aws", Credentials: missionv1alpha1.CredentialConfig{Name: "aws-key"}},
+			{Provider: "azure", Credentials: missionv1alpha1.CredentialConfig{Name: "azure-key"}},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mission).Build()
+	validator := &StorageBucketsCustomValidator{MissionClient: clients.MissionClient{Client: c}}
+
+	cases := map[string]struct {
+		key   string
+		name  string
+		valid bool
+	}{
+		"valid aws":         {"aws-key", "sample.bucket", true},
+		"aws ip address":    {"aws-key", "192.168.1.1", false},
+		"too short":         {"aws-key", "ab", false},
+		"valid azure":       {"azure-key", "sample-bucket", true},
+		"azure dots":        {"azure-key", "sample.bucket", false},
+		"package not found": {"gcp-key", "sample-bucket", false},
+		"uppercase in name": {"aws-key", "Sample-Bucket", false},
+	}
+	for name, c := range cases {
+		bucket := &storagev1alpha1.StorageBuckets{
+			ObjectMeta: metav1.ObjectMeta{Name: "bucket"},
+			Spec: storagev1alpha1.StorageBucketsSpec{
+				MissionRef:  storagev1alpha1.StorageBucketMissionRef{MissionName: "mission", MissionKey: c.key},
+				ForProvider: storagev1alpha1.ProviderData{Name: c.name},
+			},
+		}
+		_, err := validator.ValidateCreate(context.Background(), bucket)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDefaultStorageBuckets(t *testing.T) {
+	bucket := &storagev1alpha1.StorageBuckets{ObjectMeta: metav1.ObjectMeta{Name: "bucket"}}
+	if err := (&StorageBucketsCustomDefaulter{}).Default(context.Background(), bucket); err != nil {
+		t.Fatal(err)
+	}
+	if bucket.Spec.ForProvider.Name != "bucket" {
+		t.Errorf("expected bucket name to default to the resource name, got %s", bucket.Spec.ForProvider.Name)
+	}
+}